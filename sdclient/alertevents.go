@@ -0,0 +1,412 @@
+package sdclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultAlertEventPageSize is the number of events requested per page when AlertEventFilter.Limit is not set
+const DefaultAlertEventPageSize = 100
+
+// AlertEvents represents a list of alert events response object
+type AlertEvents struct {
+	Events []AlertEvent `json:"notifications"`
+}
+
+// AlertEventItem represents a single alert event request/response object
+type AlertEventItem struct {
+	Event AlertEvent `json:"notification"`
+}
+
+// AlertEvent represents a single firing or resolution of an alert
+type AlertEvent struct {
+	ID           int
+	AlertID      int
+	AlertName    string
+	Timestamp    time.Time
+	State        string
+	Severity     int
+	Scope        string
+	Condition    string
+	Resolved     bool
+	Acknowledged bool
+	Entities     []AlertEventEntity
+}
+
+// AlertEventEntity represents an entity that triggered an alert event
+type AlertEventEntity struct {
+	Entity         string                 `json:"entity,omitempty"`
+	MetricValues   []AlertEventMetric     `json:"metricValues,omitempty"`
+	AdditionalInfo []AlertEventAdditional `json:"additionalInfo,omitempty"`
+}
+
+// AlertEventMetric represents a metric value that triggered an alert event
+type AlertEventMetric struct {
+	Metric           string  `json:"metric,omitempty"`
+	Aggregation      string  `json:"aggregation,omitempty"`
+	GroupAggregation string  `json:"groupAggregation,omitempty"`
+	Value            float64 `json:"value"`
+}
+
+// AlertEventAdditional represents an additional label attached to an alert event entity
+type AlertEventAdditional struct {
+	Metric string `json:"metric,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// alertEventJSON is the wire format of AlertEvent, timestamps are in microseconds
+type alertEventJSON struct {
+	ID           int                `json:"id,omitempty"`
+	Timestamp    int64              `json:"timestamp,omitempty"`
+	State        string             `json:"state,omitempty"`
+	Severity     int                `json:"severity,omitempty"`
+	Scope        string             `json:"scope,omitempty"`
+	Condition    string             `json:"condition,omitempty"`
+	Resolved     bool               `json:"resolved"`
+	Acknowledged bool               `json:"acknowledged"`
+	Entities     []AlertEventEntity `json:"entities,omitempty"`
+	Alert        *alertEventAlert   `json:"alert,omitempty"`
+}
+
+type alertEventAlert struct {
+	ID       int    `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Severity int    `json:"severity,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// UnmarshalJSON decodes an alert event converting its timestamp to time.Time
+func (e *AlertEvent) UnmarshalJSON(data []byte) error {
+	var raw alertEventJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = AlertEvent{
+		ID:           raw.ID,
+		Timestamp:    fromMicros(raw.Timestamp),
+		State:        raw.State,
+		Severity:     raw.Severity,
+		Scope:        raw.Scope,
+		Condition:    raw.Condition,
+		Resolved:     raw.Resolved,
+		Acknowledged: raw.Acknowledged,
+		Entities:     raw.Entities,
+	}
+
+	if raw.Alert != nil {
+		e.AlertID = raw.Alert.ID
+		e.AlertName = raw.Alert.Name
+		if e.Severity == 0 {
+			e.Severity = raw.Alert.Severity
+		}
+		if e.Scope == "" {
+			e.Scope = raw.Alert.Scope
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes an alert event in the API format
+func (e AlertEvent) MarshalJSON() ([]byte, error) {
+	raw := alertEventJSON{
+		ID:           e.ID,
+		Timestamp:    toMicros(e.Timestamp),
+		State:        e.State,
+		Severity:     e.Severity,
+		Scope:        e.Scope,
+		Condition:    e.Condition,
+		Resolved:     e.Resolved,
+		Acknowledged: e.Acknowledged,
+		Entities:     e.Entities,
+	}
+
+	if e.AlertID != 0 || e.AlertName != "" {
+		raw.Alert = &alertEventAlert{ID: e.AlertID, Name: e.AlertName}
+	}
+
+	return json.Marshal(raw)
+}
+
+// AlertEventFilter selects alert events, zero values are not filtered on
+type AlertEventFilter struct {
+	From     time.Time
+	To       time.Time
+	AlertID  int
+	State    string
+	Severity *int
+	Scope    string
+	// Limit is the page size, DefaultAlertEventPageSize if zero
+	Limit int
+}
+
+func (f AlertEventFilter) query() url.Values {
+	q := url.Values{}
+
+	if !f.From.IsZero() {
+		q.Set("from", strconv.FormatInt(toMicros(f.From), 10))
+	}
+	if !f.To.IsZero() {
+		q.Set("to", strconv.FormatInt(toMicros(f.To), 10))
+	}
+	if f.AlertID != 0 {
+		q.Set("alertId", strconv.Itoa(f.AlertID))
+	}
+	if f.State != "" {
+		q.Set("state", f.State)
+	}
+	if f.Severity != nil {
+		q.Set("severity", strconv.Itoa(*f.Severity))
+	}
+	if f.Scope != "" {
+		q.Set("filter", f.Scope)
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultAlertEventPageSize
+	}
+	q.Set("limit", strconv.Itoa(limit))
+
+	return q
+}
+
+// ListAlertEvents returns a single page of alert events, newest first
+func (c *Client) ListAlertEvents(filter AlertEventFilter) (*AlertEvents, error) {
+	return c.ListAlertEventsWithContext(context.Background(), filter)
+}
+
+// ListAlertEventsWithContext returns a single page of alert events, newest first
+func (c *Client) ListAlertEventsWithContext(ctx context.Context, filter AlertEventFilter) (*AlertEvents, error) {
+
+	fullURL := fmt.Sprintf("%s%s?%s", c.Endpoint, URI_ALERT_EVENTS, filter.query().Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = new(AlertEvents)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAlertEvent returns an alert event by ID
+func (c *Client) GetAlertEvent(id int) (*AlertEventItem, error) {
+	return c.GetAlertEventWithContext(context.Background(), id)
+}
+
+// GetAlertEventWithContext returns an alert event by ID
+func (c *Client) GetAlertEventWithContext(ctx context.Context, id int) (*AlertEventItem, error) {
+
+	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_ALERT_EVENTS, id)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = new(AlertEventItem)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AcknowledgeAlertEvent acknowledges an alert event
+func (c *Client) AcknowledgeAlertEvent(id int) (*AlertEventItem, error) {
+	return c.AcknowledgeAlertEventWithContext(context.Background(), id)
+}
+
+// AcknowledgeAlertEventWithContext acknowledges an alert event
+func (c *Client) AcknowledgeAlertEventWithContext(ctx context.Context, id int) (*AlertEventItem, error) {
+	return c.updateAlertEvent(ctx, id, func(e *AlertEvent) {
+		e.Acknowledged = true
+	})
+}
+
+// ResolveAlertEvent marks an alert event as resolved
+func (c *Client) ResolveAlertEvent(id int) (*AlertEventItem, error) {
+	return c.ResolveAlertEventWithContext(context.Background(), id)
+}
+
+// ResolveAlertEventWithContext marks an alert event as resolved
+func (c *Client) ResolveAlertEventWithContext(ctx context.Context, id int) (*AlertEventItem, error) {
+	return c.updateAlertEvent(ctx, id, func(e *AlertEvent) {
+		e.Resolved = true
+	})
+}
+
+func (c *Client) updateAlertEvent(ctx context.Context, id int, update func(*AlertEvent)) (*AlertEventItem, error) {
+
+	event, err := c.GetAlertEventWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update(&event.Event)
+
+	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_ALERT_EVENTS, id)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res = new(AlertEventItem)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AlertEventIterator pages through alert events from newest to oldest.
+// Iteration ends on an empty page. When a full page shares one timestamp the page size is doubled,
+// an error is reported if the server still returns no new events.
+type AlertEventIterator struct {
+	client *Client
+	ctx    context.Context
+	filter AlertEventFilter
+
+	page []AlertEvent
+	pos  int
+	cur  AlertEvent
+	// seen holds IDs of events at the page boundary timestamp which the next page returns again
+	seen map[int]bool
+	// grown is set when the page size was raised to get past events sharing the boundary timestamp
+	grown bool
+	done  bool
+	err   error
+}
+
+// IterateAlertEvents returns an iterator over all alert events matching the filter
+func (c *Client) IterateAlertEvents(filter AlertEventFilter) *AlertEventIterator {
+	return c.IterateAlertEventsWithContext(context.Background(), filter)
+}
+
+// IterateAlertEventsWithContext returns an iterator over all alert events matching the filter
+func (c *Client) IterateAlertEventsWithContext(ctx context.Context, filter AlertEventFilter) *AlertEventIterator {
+	return &AlertEventIterator{
+		client: c,
+		ctx:    ctx,
+		filter: filter,
+	}
+}
+
+// Next advances the iterator, it returns false when there are no more events or an error occurred
+func (it *AlertEventIterator) Next() bool {
+	for it.pos >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur = it.page[it.pos]
+	it.pos++
+
+	return true
+}
+
+// Event returns the current event
+func (it *AlertEventIterator) Event() AlertEvent {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration
+func (it *AlertEventIterator) Err() error {
+	return it.err
+}
+
+func (it *AlertEventIterator) fetch() {
+	res, err := it.client.ListAlertEventsWithContext(it.ctx, it.filter)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if len(res.Events) == 0 {
+		it.done = true
+		return
+	}
+
+	limit := it.filter.Limit
+	if limit <= 0 {
+		limit = DefaultAlertEventPageSize
+	}
+
+	it.page = it.page[:0]
+	it.pos = 0
+
+	for _, e := range res.Events {
+		if it.seen[e.ID] {
+			continue
+		}
+		it.page = append(it.page, e)
+	}
+
+	if len(it.page) == 0 {
+		switch {
+		case len(res.Events) >= limit:
+			// a full page of events sharing the boundary timestamp, a larger page is needed to get past them
+			it.filter.Limit = limit * 2
+			it.grown = true
+		case it.grown:
+			it.err = fmt.Errorf("alert events at %s could not be paged past, the server returned %d of %d requested events",
+				it.filter.To.Format(time.RFC3339Nano), len(res.Events), limit)
+		default:
+			it.done = true
+		}
+		return
+	}
+
+	it.grown = false
+
+	// the next page ends at the oldest timestamp of this page, events at that timestamp are skipped when returned again
+	oldest := it.page[len(it.page)-1].Timestamp
+	if it.seen == nil || !it.filter.To.Equal(oldest) {
+		it.seen = make(map[int]bool)
+	}
+	for _, e := range it.page {
+		if e.Timestamp.Equal(oldest) {
+			it.seen[e.ID] = true
+		}
+	}
+	it.filter.To = oldest
+}
+
+func fromMicros(us int64) time.Time {
+	if us == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, us*int64(time.Microsecond))
+}
+
+func toMicros(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Microsecond)
+}
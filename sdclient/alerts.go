@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 type Alert struct {
@@ -16,6 +17,12 @@ type Alerts struct {
 	Alerts []*AlertItem `json:"alerts,omitempty"`
 }
 
+// AlertsV2 is a list of alerts from the v2 alerts API.
+// The v2 alert model differs from AlertItem, alerts are kept as returned by the API.
+type AlertsV2 struct {
+	Alerts []json.RawMessage `json:"alerts,omitempty"`
+}
+
 type AlertItem struct {
 	ID                     int                       `json:"id,omitempty"`
 	Version                int                       `json:"version,omitempty"`
@@ -99,6 +106,30 @@ func (c *Client) ListAlertsWithContext(ctx context.Context) (*Alerts, error) {
 	return res, nil
 }
 
+// ListAlertsV2 returns a list of alerts from the v2 alerts API
+func (c *Client) ListAlertsV2() (*AlertsV2, error) {
+	return c.ListAlertsV2WithContext(context.Background())
+}
+
+// ListAlertsV2WithContext returns a list of alerts from the v2 alerts API
+func (c *Client) ListAlertsV2WithContext(ctx context.Context) (*AlertsV2, error) {
+
+	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_ALERTS_V2)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = new(AlertsV2)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // GetAlert returns an alert by ID
 func (c *Client) GetAlert(id int) (*Alert, error) {
 	return c.GetAlertWithContext(context.Background(), id)
@@ -131,6 +162,27 @@ func (c *Client) CreateAlerts(alerts *Alerts) (*Alerts, error) {
 // CreateAlertsWithContext creates a new alerts from provided alerts object
 func (c *Client) CreateAlertsWithContext(ctx context.Context, alerts *Alerts) (*Alerts, error) {
 
+	if c.ValidateChannels {
+		var errs ValidationErrors
+		for i, alert := range alerts.Alerts {
+			prefix := fmt.Sprintf("alerts[%d].", i)
+
+			if alert == nil {
+				errs.add(strings.TrimSuffix(prefix, "."), "alert is nil")
+				continue
+			}
+
+			alertErrs, err := c.checkAlertChannels(ctx, alert, prefix)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, alertErrs...)
+		}
+		if err := errs.err(); err != nil {
+			return nil, err
+		}
+	}
+
 	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_ALERTS_V2)
 
 	byteBody, err := json.MarshalIndent(alerts, "", "  ")
@@ -162,6 +214,12 @@ func (c *Client) CreateAlert(alert *Alert) (*Alert, error) {
 // CreateAlertWithContext creates a new alerts from provided alerts object
 func (c *Client) CreateAlertWithContext(ctx context.Context, alert *Alert) (*Alert, error) {
 
+	if c.ValidateChannels {
+		if err := c.CheckAlertChannelsWithContext(ctx, &alert.Alert); err != nil {
+			return nil, err
+		}
+	}
+
 	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_ALERTS)
 
 	byteBody, err := json.MarshalIndent(alert, "", "  ")
@@ -193,6 +251,12 @@ func (c *Client) UpdateAlert(alert *Alert) (*Alert, error) {
 // UpdateAlertWithContext updates an alert
 func (c *Client) UpdateAlertWithContext(ctx context.Context, alert *Alert) (*Alert, error) {
 
+	if c.ValidateChannels {
+		if err := c.CheckAlertChannelsWithContext(ctx, &alert.Alert); err != nil {
+			return nil, err
+		}
+	}
+
 	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_ALERTS, alert.Alert.ID)
 
 	byteBody, err := json.MarshalIndent(alert, "", "  ")
@@ -0,0 +1,239 @@
+package sdclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+)
+
+const (
+	alertTemplateLeftDelim  = "[["
+	alertTemplateRightDelim = "]]"
+)
+
+// AlertTemplate expands a base alert into concrete alerts.
+// Name, Description, Filter, Condition and the CustomNotification texts of the base alert
+// are text/template templates executed with the Vars of AlertParams, e.g. [[ .service ]].
+// Templates use [[ and ]] delimiters so Sysdig placeholders like {{__alert_name__}} are kept as they are.
+type AlertTemplate struct {
+	base      AlertItem
+	templates map[string]*template.Template
+	channels  ChannelSelector
+}
+
+// ChannelSelector returns notification channels of an alert expanded with vars
+type ChannelSelector func(vars map[string]interface{}) ([]int, error)
+
+// ChannelsByVar returns a ChannelSelector picking channels by the value of a template variable,
+// a value without channels is an error
+func ChannelsByVar(variable string, channels map[string][]int) ChannelSelector {
+	return func(vars map[string]interface{}) ([]int, error) {
+		v, ok := vars[variable]
+		if !ok {
+			return nil, fmt.Errorf("variable %q is not set", variable)
+		}
+
+		ids, ok := channels[fmt.Sprint(v)]
+		if !ok {
+			return nil, fmt.Errorf("no notification channels for %s %q", variable, fmt.Sprint(v))
+		}
+
+		return ids, nil
+	}
+}
+
+// AlertParams holds parameters used to expand an AlertTemplate into a single alert
+type AlertParams struct {
+	// Vars is the data passed to the templates
+	Vars map[string]interface{}
+	// NotificationChannelIds overrides notification channels of the base alert and of the template's
+	// ChannelSelector when not nil
+	NotificationChannelIds []int
+}
+
+// ParamMatrix maps template variables to the list of their possible values
+type ParamMatrix map[string][]interface{}
+
+// NewAlertTemplate parses templates of the base alert
+func NewAlertTemplate(base AlertItem) (*AlertTemplate, error) {
+	t := &AlertTemplate{
+		base:      base,
+		templates: make(map[string]*template.Template),
+	}
+
+	for field, text := range alertTemplateFields(&base) {
+		if *text == "" {
+			continue
+		}
+
+		tmpl, err := template.New(field).Delims(alertTemplateLeftDelim, alertTemplateRightDelim).Option("missingkey=error").Parse(*text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s template: %w", field, err)
+		}
+
+		t.templates[field] = tmpl
+	}
+
+	return t, nil
+}
+
+// WithChannels sets the selector of notification channels used for params without NotificationChannelIds,
+// e.g. to vary channels across the combinations of a ParamMatrix.
+func (t *AlertTemplate) WithChannels(fn ChannelSelector) *AlertTemplate {
+	t.channels = fn
+	return t
+}
+
+// Execute expands the template into a single alert
+func (t *AlertTemplate) Execute(params AlertParams) (*AlertItem, error) {
+	alert := copyAlertItem(t.base)
+
+	switch {
+	case params.NotificationChannelIds != nil:
+		alert.NotificationChannelIds = append([]int(nil), params.NotificationChannelIds...)
+	case t.channels != nil:
+		ids, err := t.channels(params.Vars)
+		if err != nil {
+			return nil, fmt.Errorf("selecting notification channels: %w", err)
+		}
+		alert.NotificationChannelIds = append([]int(nil), ids...)
+	}
+
+	for field, text := range alertTemplateFields(alert) {
+		tmpl, ok := t.templates[field]
+		if !ok {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, params.Vars); err != nil {
+			return nil, fmt.Errorf("executing %s template: %w", field, err)
+		}
+
+		*text = buf.String()
+	}
+
+	return alert, nil
+}
+
+// copyAlertItem returns a copy of the alert not sharing slices and nested objects with it
+func copyAlertItem(a AlertItem) *AlertItem {
+	res := a
+
+	res.NotificationChannelIds = append([]int(nil), a.NotificationChannelIds...)
+	res.InvalidMetrics = append([]string(nil), a.InvalidMetrics...)
+	res.Links = append([]string(nil), a.Links...)
+	res.SegmentBy = append([]string(nil), a.SegmentBy...)
+
+	if a.CustomNotification != nil {
+		cn := *a.CustomNotification
+		res.CustomNotification = &cn
+	}
+
+	if a.SysdigCapture != nil {
+		sc := *a.SysdigCapture
+		res.SysdigCapture = &sc
+	}
+
+	if a.SegmentCondition != nil {
+		sc := *a.SegmentCondition
+		res.SegmentCondition = &sc
+	}
+
+	return &res
+}
+
+// Expand expands the template once for every params and validates the resulting alerts.
+// The result can be passed directly to CreateAlerts.
+func (t *AlertTemplate) Expand(params []AlertParams) (*Alerts, error) {
+	res := &Alerts{Alerts: make([]*AlertItem, 0, len(params))}
+	names := make(map[string]int, len(params))
+
+	for i, p := range params {
+		alert, err := t.Execute(p)
+		if err != nil {
+			return nil, fmt.Errorf("params %d: %w", i, err)
+		}
+
+		if j, ok := names[alert.Name]; ok {
+			return nil, fmt.Errorf("params %d: alert name %q already produced by params %d", i, alert.Name, j)
+		}
+		names[alert.Name] = i
+
+		res.Alerts = append(res.Alerts, alert)
+	}
+
+	if err := res.ValidateAll(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ExpandMatrix expands the template for every combination of values in the matrix,
+// notification channels of a combination are selected with the template's ChannelSelector if set
+func (t *AlertTemplate) ExpandMatrix(m ParamMatrix) (*Alerts, error) {
+	params, err := m.Params()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Expand(params)
+}
+
+// Params returns the cartesian product of the matrix values, ordered by variable name
+func (m ParamMatrix) Params() ([]AlertParams, error) {
+	keys := make([]string, 0, len(m))
+	for k, values := range m {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no values for variable %q", k)
+		}
+		keys = append(keys, k)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("parameter matrix is empty")
+	}
+
+	sort.Strings(keys)
+
+	res := []AlertParams{{Vars: map[string]interface{}{}}}
+
+	for _, k := range keys {
+		next := make([]AlertParams, 0, len(res)*len(m[k]))
+		for _, p := range res {
+			for _, v := range m[k] {
+				vars := make(map[string]interface{}, len(p.Vars)+1)
+				for pk, pv := range p.Vars {
+					vars[pk] = pv
+				}
+				vars[k] = v
+				next = append(next, AlertParams{Vars: vars})
+			}
+		}
+		res = next
+	}
+
+	return res, nil
+}
+
+// alertTemplateFields returns pointers to the templated fields of the alert
+func alertTemplateFields(a *AlertItem) map[string]*string {
+	fields := map[string]*string{
+		"name":        &a.Name,
+		"description": &a.Description,
+		"filter":      &a.Filter,
+		"condition":   &a.Condition,
+	}
+
+	if a.CustomNotification != nil {
+		fields["customNotification.titleTemplate"] = &a.CustomNotification.TitleTemplate
+		fields["customNotification.subject"] = &a.CustomNotification.Subject
+		fields["customNotification.prependText"] = &a.CustomNotification.PrependText
+		fields["customNotification.appendText"] = &a.CustomNotification.AppendText
+	}
+
+	return fields
+}
@@ -0,0 +1,853 @@
+package sdclient
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// BackupFormatVersion is the version of the backup archive layout written by Backup.Write
+const BackupFormatVersion = 1
+
+const (
+	backupManifestFile         = "manifest.json"
+	backupManifestChecksumFile = "manifest.sha256"
+	backupAlertsFile           = "alerts.json"
+	backupAlertsV2File         = "alerts_v2.json"
+	backupChannelsFile         = "notification_channels.json"
+	backupSilencingRulesFile   = "silencing_rules.json"
+	backupTeamsFile            = "teams.json"
+)
+
+// Backup is a snapshot of all alerts, notification channels, silencing rules and teams of a tenant.
+// Resources are kept as returned by the API so fields not modelled by this package are preserved.
+type Backup struct {
+	Manifest             BackupManifest
+	Alerts               []json.RawMessage
+	AlertsV2             []json.RawMessage
+	NotificationChannels []json.RawMessage
+	SilencingRules       []json.RawMessage
+	Teams                []json.RawMessage
+	// IncludeSecrets disables redaction of notification channel secrets when the backup is written.
+	// Redacted secrets are written as ${env:SDCLIENT_CHANNEL_<id>_SECRET_<n>} references, listed in Manifest.Secrets.
+	IncludeSecrets bool
+}
+
+// BackupManifest describes the content of a backup archive
+type BackupManifest struct {
+	FormatVersion int          `json:"formatVersion"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	Endpoint      string       `json:"endpoint,omitempty"`
+	Files         []BackupFile `json:"files"`
+	// Secrets maps secret references written in place of notification channel secrets to channel names
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+// BackupFile describes a single JSON document stored in a backup archive
+type BackupFile struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// RestoreEntry describes the outcome of restoring a single resource
+type RestoreEntry struct {
+	Kind   string
+	Name   string
+	OldID  int
+	NewID  int
+	Reason string
+	Err    error
+}
+
+// RestoreReport summarizes the result of a restore
+type RestoreReport struct {
+	// IDMap maps old IDs from the backup to IDs in the target tenant, per resource kind
+	IDMap    map[string]map[int]int
+	Restored []RestoreEntry
+	Skipped  []RestoreEntry
+	// Failed lists resources that could not be decoded, remapped or created
+	Failed []RestoreEntry
+}
+
+// backupRef holds the fields of a backed up resource needed to restore it and remap references to it
+type backupRef struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	TeamID int    `json:"teamId"`
+}
+
+// Backup takes a snapshot of the tenant
+func (c *Client) Backup() (*Backup, error) {
+	return c.BackupWithContext(context.Background())
+}
+
+// BackupWithContext takes a snapshot of the tenant
+func (c *Client) BackupWithContext(ctx context.Context) (*Backup, error) {
+
+	b := &Backup{
+		Manifest: BackupManifest{
+			FormatVersion: BackupFormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			Endpoint:      c.Endpoint,
+		},
+	}
+
+	lists := []struct {
+		what   string
+		uri    string
+		key    string
+		target *[]json.RawMessage
+	}{
+		{"alerts", URI_ALERTS, "alerts", &b.Alerts},
+		{"v2 alerts", URI_ALERTS_V2, "alerts", &b.AlertsV2},
+		{"notification channels", URI_CHANNELS, "notificationChannels", &b.NotificationChannels},
+		{"silencing rules", URI_SILENCERULES, "", &b.SilencingRules},
+		{"teams", URI_TEAMS, "teams", &b.Teams},
+	}
+
+	for _, l := range lists {
+		docs, err := c.listDocuments(ctx, l.uri, l.key)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", l.what, err)
+		}
+		*l.target = docs
+	}
+
+	return b, nil
+}
+
+// Write writes the backup as a gzipped tar archive of JSON documents with a manifest and checksums.
+// Notification channel secrets are replaced by secret references unless IncludeSecrets is set,
+// restore resolves them with the client's SecretResolver.
+func (b *Backup) Write(w io.Writer) error {
+
+	type document struct {
+		name string
+		docs []json.RawMessage
+	}
+
+	secrets := make(map[string]string)
+
+	channels := b.NotificationChannels
+	if !b.IncludeSecrets {
+		channels = make([]json.RawMessage, 0, len(b.NotificationChannels))
+		for _, raw := range b.NotificationChannels {
+			redacted, err := mapChannelDocument(raw, func(nc NotificationChannelItem) (NotificationChannelItem, error) {
+				res, refs, err := nc.redactedAsReferences()
+				for _, ref := range refs {
+					secrets[ref] = nc.Name
+				}
+				return res, err
+			})
+			if err != nil {
+				return fmt.Errorf("redacting notification channel: %w", err)
+			}
+			channels = append(channels, redacted)
+		}
+	}
+
+	docs := []document{
+		{backupAlertsFile, b.Alerts},
+		{backupAlertsV2File, b.AlertsV2},
+		{backupChannelsFile, channels},
+		{backupSilencingRulesFile, b.SilencingRules},
+		{backupTeamsFile, b.Teams},
+	}
+
+	manifest := b.Manifest
+	manifest.FormatVersion = BackupFormatVersion
+	if manifest.CreatedAt.IsZero() {
+		manifest.CreatedAt = time.Now().UTC()
+	}
+	manifest.Files = nil
+	manifest.Secrets = nil
+	if len(secrets) > 0 {
+		manifest.Secrets = secrets
+	}
+
+	contents := make(map[string][]byte, len(docs))
+
+	for _, d := range docs {
+		list := d.docs
+		if list == nil {
+			list = []json.RawMessage{}
+		}
+
+		body, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", d.name, err)
+		}
+
+		sum := sha256.Sum256(body)
+
+		manifest.Files = append(manifest.Files, BackupFile{
+			Name:   d.name,
+			Count:  len(d.docs),
+			Size:   int64(len(body)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+
+		contents[d.name] = body
+	}
+
+	manifestBody, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	manifestSum := sha256.Sum256(manifestBody)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := writeTarFile(tw, backupManifestFile, manifestBody, manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := writeTarFile(tw, backupManifestChecksumFile, []byte(hex.EncodeToString(manifestSum[:])+"\n"), manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, f := range manifest.Files {
+		if err := writeTarFile(tw, f.Name, contents[f.Name], manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// ReadBackup reads a backup archive written by Backup.Write and verifies its checksums
+func ReadBackup(r io.Reader) (*Backup, error) {
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		files[hdr.Name] = body
+	}
+
+	manifestBody, ok := files[backupManifestFile]
+	if !ok {
+		return nil, errors.New("backup archive has no manifest")
+	}
+
+	checksum, ok := files[backupManifestChecksumFile]
+	if !ok {
+		return nil, errors.New("backup archive has no manifest checksum")
+	}
+
+	sum := sha256.Sum256(manifestBody)
+	if string(bytes.TrimSpace(checksum)) != hex.EncodeToString(sum[:]) {
+		return nil, errors.New("backup manifest checksum mismatch")
+	}
+
+	var b = new(Backup)
+
+	if err := json.Unmarshal(manifestBody, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	if b.Manifest.FormatVersion != BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", b.Manifest.FormatVersion)
+	}
+
+	targets := map[string]*[]json.RawMessage{
+		backupAlertsFile:         &b.Alerts,
+		backupAlertsV2File:       &b.AlertsV2,
+		backupChannelsFile:       &b.NotificationChannels,
+		backupSilencingRulesFile: &b.SilencingRules,
+		backupTeamsFile:          &b.Teams,
+	}
+
+	for _, f := range b.Manifest.Files {
+		body, ok := files[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("backup archive is missing %s", f.Name)
+		}
+
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", f.Name)
+		}
+
+		target, ok := targets[f.Name]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(body, target); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", f.Name, err)
+		}
+	}
+
+	return b, nil
+}
+
+// Restore recreates the resources of a backup in the tenant
+func (c *Client) Restore(b *Backup) (*RestoreReport, error) {
+	return c.RestoreWithContext(context.Background(), b)
+}
+
+// RestoreWithContext recreates the resources of a backup in the tenant.
+// Resources that already exist in the tenant with the same name are not recreated,
+// their IDs are used instead when remapping references between resources.
+// Resources are recreated from the backed up documents, only IDs and server managed fields are changed.
+// Secret references written in place of notification channel secrets are resolved with the client's SecretResolver,
+// see Backup.IncludeSecrets. Channels with secrets redacted as plain text cannot be restored.
+// v2 alerts are recreated with the v2 alerts API, v1 alerts are recreated only when they are not in the v2 list.
+// Resources that cannot be restored are reported in the report, an error is returned when resources of the tenant
+// cannot be listed.
+func (c *Client) RestoreWithContext(ctx context.Context, b *Backup) (*RestoreReport, error) {
+
+	report := &RestoreReport{
+		IDMap: map[string]map[int]int{
+			RESOURCE_TEAM:                 {},
+			RESOURCE_NOTIFICATION_CHANNEL: {},
+			RESOURCE_ALERT:                {},
+			RESOURCE_SILENCING_RULE:       {},
+		},
+	}
+
+	if err := c.restoreTeams(ctx, b, report); err != nil {
+		return report, err
+	}
+
+	if err := c.restoreNotificationChannels(ctx, b, report); err != nil {
+		return report, err
+	}
+
+	if err := c.restoreAlerts(ctx, b, report); err != nil {
+		return report, err
+	}
+
+	if err := c.restoreSilencingRules(ctx, b, report); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// restoreSpec describes how documents of a resource kind are recreated
+type restoreSpec struct {
+	kind string
+	uri  string
+	// requestKey and responseKey wrap the document in the create request and response, unwrapped if empty
+	requestKey  string
+	responseKey string
+	// drop lists server managed fields removed before the document is created
+	drop []string
+	// key identifies a resource when matching backed up resources with existing ones
+	key func(ref backupRef) string
+	// prepare adjusts the document before it is created, an error fails the resource
+	prepare func(doc map[string]json.RawMessage) error
+}
+
+func byName(ref backupRef) string {
+	return ref.Name
+}
+
+// restoreDocuments creates the documents that do not exist yet, existing maps keys of existing resources to their IDs
+func (c *Client) restoreDocuments(ctx context.Context, spec restoreSpec, docs []json.RawMessage, existing map[string]int, report *RestoreReport) {
+
+	for _, raw := range docs {
+		var ref backupRef
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			report.Failed = append(report.Failed, RestoreEntry{Kind: spec.kind, Err: fmt.Errorf("decoding %s: %w", spec.kind, err)})
+			continue
+		}
+
+		entry := RestoreEntry{Kind: spec.kind, Name: ref.Name, OldID: ref.ID}
+
+		if id, ok := existing[spec.key(ref)]; ok {
+			entry.NewID = id
+			entry.Reason = fmt.Sprintf("%s already exists", spec.kind)
+			report.IDMap[spec.kind][ref.ID] = id
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		doc := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			entry.Err = fmt.Errorf("decoding %s: %w", spec.kind, err)
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		for _, k := range append([]string{"id", "version", "createdOn", "modifiedOn", "customerId"}, spec.drop...) {
+			delete(doc, k)
+		}
+
+		if err := remapDocument(doc, report); err != nil {
+			entry.Err = err
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		if spec.prepare != nil {
+			if err := spec.prepare(doc); err != nil {
+				entry.Err = err
+				report.Failed = append(report.Failed, entry)
+				continue
+			}
+		}
+
+		id, err := c.createDocument(ctx, spec.uri, spec.requestKey, spec.responseKey, doc)
+		if err != nil {
+			entry.Err = err
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		entry.NewID = id
+		existing[spec.key(ref)] = id
+		report.IDMap[spec.kind][ref.ID] = id
+		report.Restored = append(report.Restored, entry)
+	}
+}
+
+func (c *Client) restoreTeams(ctx context.Context, b *Backup, report *RestoreReport) error {
+
+	if len(b.Teams) == 0 {
+		return nil
+	}
+
+	teams, err := c.ListTeamsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing teams: %w", err)
+	}
+
+	existing := make(map[string]int, len(teams.Teams))
+	for _, t := range teams.Teams {
+		existing[t.Name] = t.ID
+	}
+
+	spec := restoreSpec{
+		kind:        RESOURCE_TEAM,
+		uri:         URI_TEAMS,
+		responseKey: "team",
+		drop:        []string{"dateCreated", "lastUpdated", "userCount", "usersRole", "immutable", "default"},
+		key:         byName,
+		prepare: func(doc map[string]json.RawMessage) error {
+			if _, ok := doc["users"]; !ok {
+				doc["users"] = json.RawMessage("[]")
+			}
+			return nil
+		},
+	}
+
+	c.restoreDocuments(ctx, spec, b.Teams, existing, report)
+
+	return nil
+}
+
+func (c *Client) restoreNotificationChannels(ctx context.Context, b *Backup, report *RestoreReport) error {
+
+	if len(b.NotificationChannels) == 0 {
+		return nil
+	}
+
+	channels, err := c.ListNotificationChannelsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing notification channels: %w", err)
+	}
+
+	existing := make(map[string]int, len(channels.NotificationChannels))
+	for _, ch := range channels.NotificationChannels {
+		existing[ch.Type+"/"+ch.Name] = ch.ID
+	}
+
+	spec := restoreSpec{
+		kind:        RESOURCE_NOTIFICATION_CHANNEL,
+		uri:         URI_CHANNELS,
+		requestKey:  "notificationChannel",
+		responseKey: "notificationChannel",
+		drop:        []string{"settingsId", "sendTestNotification"},
+		key: func(ref backupRef) string {
+			return ref.Type + "/" + ref.Name
+		},
+		prepare: func(doc map[string]json.RawMessage) error {
+			return mapChannelFields(doc, func(nc NotificationChannelItem) (NotificationChannelItem, error) {
+				if nc.hasRedactedSecrets() {
+					return nc, errors.New("notification channel secrets are redacted in the backup")
+				}
+				return c.resolveSecrets(nc)
+			})
+		},
+	}
+
+	defer c.InvalidateChannelCache()
+
+	c.restoreDocuments(ctx, spec, b.NotificationChannels, existing, report)
+
+	return nil
+}
+
+func (c *Client) restoreAlerts(ctx context.Context, b *Backup, report *RestoreReport) error {
+
+	if len(b.Alerts) == 0 && len(b.AlertsV2) == 0 {
+		return nil
+	}
+
+	alerts, err := c.ListAlertsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing alerts: %w", err)
+	}
+
+	alertsV2, err := c.ListAlertsV2WithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing v2 alerts: %w", err)
+	}
+
+	existing := make(map[string]int, len(alerts.Alerts))
+	for _, a := range alerts.Alerts {
+		if a != nil {
+			existing[a.Name] = a.ID
+		}
+	}
+	for _, raw := range alertsV2.Alerts {
+		var ref backupRef
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			report.Failed = append(report.Failed, RestoreEntry{Kind: RESOURCE_ALERT, Err: fmt.Errorf("decoding existing v2 alert: %w", err)})
+			continue
+		}
+		existing[ref.Name] = ref.ID
+	}
+
+	drop := []string{"lastCheckTimeInMs", "invalidMetrics"}
+
+	specV2 := restoreSpec{
+		kind:        RESOURCE_ALERT,
+		uri:         URI_ALERTS_V2,
+		requestKey:  "alert",
+		responseKey: "alert",
+		drop:        drop,
+		key:         byName,
+	}
+
+	c.restoreDocuments(ctx, specV2, b.AlertsV2, existing, report)
+
+	// alerts listed by both APIs are restored from the v2 document only,
+	// documents that cannot be decoded were reported by restoreDocuments already
+	inV2 := make(map[int]bool, len(b.AlertsV2))
+	for _, raw := range b.AlertsV2 {
+		var ref backupRef
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			continue
+		}
+		inV2[ref.ID] = true
+	}
+
+	var v1 []json.RawMessage
+	for _, raw := range b.Alerts {
+		var ref backupRef
+		if err := json.Unmarshal(raw, &ref); err == nil && inV2[ref.ID] {
+			continue
+		}
+		// an alert that cannot be decoded is reported by restoreDocuments
+		v1 = append(v1, raw)
+	}
+
+	spec := specV2
+	spec.uri = URI_ALERTS
+
+	c.restoreDocuments(ctx, spec, v1, existing, report)
+
+	return nil
+}
+
+func (c *Client) restoreSilencingRules(ctx context.Context, b *Backup, report *RestoreReport) error {
+
+	if len(b.SilencingRules) == 0 {
+		return nil
+	}
+
+	rules, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing silencing rules: %w", err)
+	}
+
+	existing := make(map[string]int, len(rules))
+	for _, r := range rules {
+		existing[r.Name] = r.ID
+	}
+
+	spec := restoreSpec{
+		kind: RESOURCE_SILENCING_RULE,
+		uri:  URI_SILENCERULES,
+		key:  byName,
+	}
+
+	c.restoreDocuments(ctx, spec, b.SilencingRules, existing, report)
+
+	return nil
+}
+
+// remapDocument translates team and notification channel references of a document to IDs in the target tenant
+func remapDocument(doc map[string]json.RawMessage, report *RestoreReport) error {
+
+	if raw, ok := doc["teamId"]; ok {
+		var teamID int
+		if err := json.Unmarshal(raw, &teamID); err != nil {
+			return fmt.Errorf("decoding teamId: %w", err)
+		}
+
+		if teamID != 0 {
+			newID, ok := report.IDMap[RESOURCE_TEAM][teamID]
+			if !ok {
+				return fmt.Errorf("team %d was not restored", teamID)
+			}
+			if err := setDocumentField(doc, "teamId", newID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if raw, ok := doc["notificationChannelIds"]; ok {
+		var ids []int
+		if err := json.Unmarshal(raw, &ids); err != nil {
+			return fmt.Errorf("decoding notificationChannelIds: %w", err)
+		}
+
+		ids, missing := remapIDs(ids, report.IDMap[RESOURCE_NOTIFICATION_CHANNEL])
+		if len(missing) > 0 {
+			return fmt.Errorf("notification channels %v were not restored", missing)
+		}
+		if err := setDocumentField(doc, "notificationChannelIds", ids); err != nil {
+			return err
+		}
+	}
+
+	// v2 alerts reference notification channels in a list of channel configurations
+	if raw, ok := doc["notificationChannelConfigList"]; ok {
+		var configs []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &configs); err != nil {
+			return fmt.Errorf("decoding notificationChannelConfigList: %w", err)
+		}
+
+		for _, cfg := range configs {
+			var id int
+			if err := json.Unmarshal(cfg["channelId"], &id); err != nil {
+				return fmt.Errorf("decoding notificationChannelConfigList: %w", err)
+			}
+
+			newID, ok := report.IDMap[RESOURCE_NOTIFICATION_CHANNEL][id]
+			if !ok {
+				return fmt.Errorf("notification channel %d was not restored", id)
+			}
+			if err := setDocumentField(cfg, "channelId", newID); err != nil {
+				return err
+			}
+		}
+
+		if err := setDocumentField(doc, "notificationChannelConfigList", configs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapChannelDocument applies fn to the notification channel of a raw document, see mapChannelFields
+func mapChannelDocument(raw json.RawMessage, fn func(NotificationChannelItem) (NotificationChannelItem, error)) (json.RawMessage, error) {
+	doc := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	if err := mapChannelFields(doc, fn); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// mapChannelFields applies fn to the notification channel decoded from doc and stores the resulting options in doc,
+// other fields of the document are left as they are
+func mapChannelFields(doc map[string]json.RawMessage, fn func(NotificationChannelItem) (NotificationChannelItem, error)) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var nc NotificationChannelItem
+	if err := json.Unmarshal(body, &nc); err != nil {
+		return err
+	}
+
+	nc, err = fn(nc)
+	if err != nil {
+		return err
+	}
+
+	if nc.Options != nil {
+		doc["options"] = *nc.Options
+	}
+
+	return nil
+}
+
+// createDocument posts a raw document and returns the ID of the created resource
+func (c *Client) createDocument(ctx context.Context, uri, requestKey, responseKey string, doc map[string]json.RawMessage) (int, error) {
+
+	fullURL := fmt.Sprintf("%s%s", c.Endpoint, uri)
+
+	var payload interface{} = doc
+	if requestKey != "" {
+		payload = map[string]interface{}{requestKey: doc}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res json.RawMessage
+
+	if err := c.sendRequest(req, &res); err != nil {
+		return 0, err
+	}
+
+	if responseKey != "" {
+		wrapped := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(res, &wrapped); err != nil {
+			return 0, err
+		}
+		res = wrapped[responseKey]
+	}
+
+	var ref backupRef
+	if err := json.Unmarshal(res, &ref); err != nil {
+		return 0, err
+	}
+
+	return ref.ID, nil
+}
+
+// listDocuments returns resources of a list endpoint as raw documents, key selects the list in a wrapped response
+func (c *Client) listDocuments(ctx context.Context, uri, key string) ([]json.RawMessage, error) {
+
+	fullURL := fmt.Sprintf("%s%s", c.Endpoint, uri)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res json.RawMessage
+
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		wrapped := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(res, &wrapped); err != nil {
+			return nil, err
+		}
+		res = wrapped[key]
+	}
+
+	var docs []json.RawMessage
+
+	if len(res) > 0 {
+		if err := json.Unmarshal(res, &docs); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// remapIDs translates ids using idMap and returns the ids that have no mapping
+func remapIDs(ids []int, idMap map[int]int) ([]int, []int) {
+	if ids == nil {
+		return nil, nil
+	}
+
+	var missing []int
+	res := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		newID, ok := idMap[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		res = append(res, newID)
+	}
+
+	return res, missing
+}
+
+func setDocumentField(doc map[string]json.RawMessage, key string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+	doc[key] = body
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, body []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(body)),
+		ModTime:  modTime,
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(body)
+	return err
+}
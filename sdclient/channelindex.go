@@ -0,0 +1,215 @@
+package sdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultChannelCacheTTL is how long the notification channel index is cached when Client.ChannelCacheTTL is not set
+const DefaultChannelCacheTTL = 5 * time.Minute
+
+// ChannelIndex is a lookup table of notification channels by ID and name
+type ChannelIndex struct {
+	byID   map[int]NotificationChannelItem
+	byName map[string][]NotificationChannelItem
+}
+
+// NewChannelIndex creates an index of the provided notification channels
+func NewChannelIndex(channels *NotificationChannels) *ChannelIndex {
+	idx := &ChannelIndex{
+		byID:   make(map[int]NotificationChannelItem),
+		byName: make(map[string][]NotificationChannelItem),
+	}
+
+	if channels == nil {
+		return idx
+	}
+
+	for _, ch := range channels.NotificationChannels {
+		idx.byID[ch.ID] = ch
+		idx.byName[ch.Name] = append(idx.byName[ch.Name], ch)
+	}
+
+	return idx
+}
+
+// ByID returns the notification channel with the given ID
+func (i *ChannelIndex) ByID(id int) (NotificationChannelItem, bool) {
+	ch, ok := i.byID[id]
+	return ch, ok
+}
+
+// ByName returns all notification channels with the given name
+func (i *ChannelIndex) ByName(name string) []NotificationChannelItem {
+	return i.byName[name]
+}
+
+type channelCache struct {
+	mu      sync.Mutex
+	index   *ChannelIndex
+	fetched time.Time
+}
+
+// NotificationChannelIndex returns the cached index of notification channels
+func (c *Client) NotificationChannelIndex() (*ChannelIndex, error) {
+	return c.NotificationChannelIndexWithContext(context.Background())
+}
+
+// NotificationChannelIndexWithContext returns the cached index of notification channels,
+// the index is fetched again once it is older than the cache TTL
+func (c *Client) NotificationChannelIndexWithContext(ctx context.Context) (*ChannelIndex, error) {
+	return c.channelIndex(ctx, false)
+}
+
+// InvalidateChannelCache drops the cached index of notification channels
+func (c *Client) InvalidateChannelCache() {
+	c.channels.mu.Lock()
+	defer c.channels.mu.Unlock()
+
+	c.channels.index = nil
+}
+
+func (c *Client) channelIndex(ctx context.Context, refresh bool) (*ChannelIndex, error) {
+	c.channels.mu.Lock()
+	defer c.channels.mu.Unlock()
+
+	ttl := c.ChannelCacheTTL
+	if ttl == 0 {
+		ttl = DefaultChannelCacheTTL
+	}
+
+	if !refresh && c.channels.index != nil && time.Since(c.channels.fetched) < ttl {
+		return c.channels.index, nil
+	}
+
+	channels, err := c.ListNotificationChannelsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.channels.index = NewChannelIndex(channels)
+	c.channels.fetched = time.Now()
+
+	return c.channels.index, nil
+}
+
+// CheckAlertChannels checks that every notification channel referenced by the alert exists,
+// is enabled and belongs to the alert's team
+func (c *Client) CheckAlertChannels(alert *AlertItem) error {
+	return c.CheckAlertChannelsWithContext(context.Background(), alert)
+}
+
+// CheckAlertChannelsWithContext checks that every notification channel referenced by the alert exists,
+// is enabled and belongs to the alert's team
+func (c *Client) CheckAlertChannelsWithContext(ctx context.Context, alert *AlertItem) error {
+	if alert == nil {
+		return errors.New("alert is nil")
+	}
+
+	errs, err := c.checkAlertChannels(ctx, alert, "")
+	if err != nil {
+		return err
+	}
+
+	return errs.err()
+}
+
+func (c *Client) checkAlertChannels(ctx context.Context, alert *AlertItem, prefix string) (ValidationErrors, error) {
+	if len(alert.NotificationChannelIds) == 0 {
+		return nil, nil
+	}
+
+	idx, err := c.channelIndex(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cached index may predate a recently created channel
+	for _, id := range alert.NotificationChannelIds {
+		if _, ok := idx.ByID(id); !ok {
+			if idx, err = c.channelIndex(ctx, true); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	var errs ValidationErrors
+
+	for i, id := range alert.NotificationChannelIds {
+		field := fmt.Sprintf("%snotificationChannelIds[%d]", prefix, i)
+
+		ch, ok := idx.ByID(id)
+		if !ok {
+			errs.add(field, "notification channel %d does not exist", id)
+			continue
+		}
+
+		if !ch.Enabled {
+			errs.add(field, "notification channel %d (%s) is disabled", id, ch.Name)
+		}
+
+		if ch.TeamID != 0 && alert.TeamID != 0 && ch.TeamID != alert.TeamID {
+			errs.add(field, "notification channel %d (%s) belongs to team %d", id, ch.Name, ch.TeamID)
+		}
+	}
+
+	return errs, nil
+}
+
+// SetAlertChannelsByName sets notification channels of the alert by channel names
+func (c *Client) SetAlertChannelsByName(alert *AlertItem, names ...string) error {
+	return c.SetAlertChannelsByNameWithContext(context.Background(), alert, names...)
+}
+
+// SetAlertChannelsByNameWithContext sets notification channels of the alert by channel names.
+// A name matching more than one channel is an error unless only one of them belongs to the alert's team.
+func (c *Client) SetAlertChannelsByNameWithContext(ctx context.Context, alert *AlertItem, names ...string) error {
+	idx, err := c.channelIndex(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if len(idx.ByName(name)) == 0 {
+			if idx, err = c.channelIndex(ctx, true); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	ids := make([]int, 0, len(names))
+
+	for _, name := range names {
+		matches := idx.ByName(name)
+
+		if len(matches) > 1 && alert.TeamID != 0 {
+			var teamMatches []NotificationChannelItem
+			for _, ch := range matches {
+				if ch.TeamID == alert.TeamID {
+					teamMatches = append(teamMatches, ch)
+				}
+			}
+			if len(teamMatches) > 0 {
+				matches = teamMatches
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return fmt.Errorf("notification channel %q not found", name)
+		case 1:
+			ids = append(ids, matches[0].ID)
+		default:
+			return fmt.Errorf("notification channel name %q is ambiguous, matches %d channels", name, len(matches))
+		}
+	}
+
+	alert.NotificationChannelIds = ids
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package sdclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultReplaceConcurrency is the number of concurrent updates when ReplaceChannelOptions.Concurrency is not set
+	DefaultReplaceConcurrency = 4
+	// DefaultReplaceMaxRetries is the number of retries after a version conflict when ReplaceChannelOptions.MaxRetries is not set
+	DefaultReplaceMaxRetries = 3
+)
+
+// ReplaceChannelOptions configures ReplaceNotificationChannel
+type ReplaceChannelOptions struct {
+	// DryRun computes the changes without updating anything
+	DryRun bool
+	// Concurrency limits the number of concurrent updates
+	Concurrency int
+	// MaxRetries limits retries of an update rejected because of a version conflict
+	MaxRetries int
+}
+
+// ChannelChange describes notification channels of a single alert or silencing rule before and after a replacement
+type ChannelChange struct {
+	Kind   string
+	ID     int
+	Name   string
+	Before []int
+	After  []int
+	// Applied is true when the resource was updated
+	Applied bool
+	Err     error
+}
+
+// ReplaceChannelResult describes the outcome of ReplaceNotificationChannel
+type ReplaceChannelResult struct {
+	OldID   int
+	NewID   int
+	DryRun  bool
+	Changes []ChannelChange
+	// Rollback reverts the applied changes, see RollbackChannelReplacement
+	Rollback *ChannelRollback
+}
+
+// Failed returns changes that could not be applied
+func (r *ReplaceChannelResult) Failed() []ChannelChange {
+	var res []ChannelChange
+	for _, ch := range r.Changes {
+		if ch.Err != nil {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+// ChannelRollback records changes applied by ReplaceNotificationChannel so they can be reverted
+type ChannelRollback struct {
+	OldID   int
+	NewID   int
+	Changes []ChannelChange
+}
+
+// channelEdit removes and adds notification channel IDs keeping other IDs in place
+type channelEdit struct {
+	remove []int
+	add    []int
+}
+
+func (e channelEdit) apply(ids []int) []int {
+	res := make([]int, 0, len(ids)+len(e.add))
+	for _, id := range ids {
+		if !containsID(e.remove, id) && !containsID(res, id) {
+			res = append(res, id)
+		}
+	}
+	for _, id := range e.add {
+		if !containsID(res, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// channelTarget is an alert or silencing rule whose notification channels are edited
+type channelTarget struct {
+	kind  string
+	alert *AlertItem
+	rule  *SilencingRule
+	edit  channelEdit
+}
+
+// ReplaceNotificationChannel replaces a notification channel with another one in all alerts and silencing rules
+func (c *Client) ReplaceNotificationChannel(oldID, newID int, opts ReplaceChannelOptions) (*ReplaceChannelResult, error) {
+	return c.ReplaceNotificationChannelWithContext(context.Background(), oldID, newID, opts)
+}
+
+// ReplaceNotificationChannelWithContext replaces a notification channel with another one in all alerts and silencing rules.
+// Updates rejected because of a version conflict are retried with the latest version of the resource.
+// The new channel must exist and be enabled, this is checked on a dry run as well.
+// Failures of single resources are reported in the result, the returned error is set only when
+// the new channel is not usable or the resources could not be listed.
+func (c *Client) ReplaceNotificationChannelWithContext(ctx context.Context, oldID, newID int, opts ReplaceChannelOptions) (*ReplaceChannelResult, error) {
+
+	if oldID == newID {
+		return nil, fmt.Errorf("notification channel %d cannot replace itself", oldID)
+	}
+
+	replacement, err := c.GetNotificationChannelWithContext(ctx, newID)
+	if err != nil {
+		return nil, fmt.Errorf("replacement notification channel %d: %w", newID, err)
+	}
+
+	if !replacement.NotificationChannel.Enabled {
+		return nil, fmt.Errorf("replacement notification channel %d is disabled", newID)
+	}
+
+	usage, err := c.ChannelUsageWithContext(ctx, oldID)
+	if err != nil {
+		return nil, err
+	}
+
+	edit := channelEdit{remove: []int{oldID}, add: []int{newID}}
+
+	var targets []channelTarget
+	for _, a := range usage.Alerts {
+		targets = append(targets, channelTarget{kind: RESOURCE_ALERT, alert: a, edit: edit})
+	}
+	for i := range usage.SilencingRules {
+		targets = append(targets, channelTarget{kind: RESOURCE_SILENCING_RULE, rule: &usage.SilencingRules[i], edit: edit})
+	}
+
+	res := &ReplaceChannelResult{
+		OldID:   oldID,
+		NewID:   newID,
+		DryRun:  opts.DryRun,
+		Changes: c.applyChannelEdits(ctx, targets, opts),
+	}
+
+	res.Rollback = &ChannelRollback{OldID: oldID, NewID: newID}
+	for _, ch := range res.Changes {
+		if ch.Applied {
+			res.Rollback.Changes = append(res.Rollback.Changes, ch)
+		}
+	}
+
+	return res, nil
+}
+
+// RollbackChannelReplacement reverts changes made by ReplaceNotificationChannel
+func (c *Client) RollbackChannelReplacement(rb *ChannelRollback, opts ReplaceChannelOptions) (*ReplaceChannelResult, error) {
+	return c.RollbackChannelReplacementWithContext(context.Background(), rb, opts)
+}
+
+// RollbackChannelReplacementWithContext reverts changes made by ReplaceNotificationChannel.
+// Only the channels added and removed by the replacement are reverted, other changes made since are kept.
+func (c *Client) RollbackChannelReplacementWithContext(ctx context.Context, rb *ChannelRollback, opts ReplaceChannelOptions) (*ReplaceChannelResult, error) {
+
+	var targets []channelTarget
+
+	for _, ch := range rb.Changes {
+		edit := channelEdit{
+			remove: idsDifference(ch.After, ch.Before),
+			add:    idsDifference(ch.Before, ch.After),
+		}
+
+		switch ch.Kind {
+		case RESOURCE_ALERT:
+			a, err := c.GetAlertWithContext(ctx, ch.ID)
+			if err != nil {
+				return nil, fmt.Errorf("getting alert %d: %w", ch.ID, err)
+			}
+			targets = append(targets, channelTarget{kind: ch.Kind, alert: &a.Alert, edit: edit})
+		case RESOURCE_SILENCING_RULE:
+			r, err := c.GetSilencingRuleWithContext(ctx, ch.ID)
+			if err != nil {
+				return nil, fmt.Errorf("getting silencing rule %d: %w", ch.ID, err)
+			}
+			targets = append(targets, channelTarget{kind: ch.Kind, rule: r, edit: edit})
+		default:
+			return nil, fmt.Errorf("unsupported resource kind %q", ch.Kind)
+		}
+	}
+
+	res := &ReplaceChannelResult{
+		OldID:   rb.NewID,
+		NewID:   rb.OldID,
+		DryRun:  opts.DryRun,
+		Changes: c.applyChannelEdits(ctx, targets, opts),
+	}
+
+	return res, nil
+}
+
+// applyChannelEdits updates targets concurrently and returns changes in the order of targets
+func (c *Client) applyChannelEdits(ctx context.Context, targets []channelTarget, opts ReplaceChannelOptions) []ChannelChange {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultReplaceConcurrency
+	}
+
+	changes := make([]ChannelChange, len(targets))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			changes[i] = c.applyChannelEdit(ctx, targets[i], opts)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return changes
+}
+
+func (c *Client) applyChannelEdit(ctx context.Context, t channelTarget, opts ReplaceChannelOptions) ChannelChange {
+	maxRetries := opts.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultReplaceMaxRetries
+	}
+
+	var change ChannelChange
+
+	for attempt := 0; ; attempt++ {
+		var err error
+
+		switch t.kind {
+		case RESOURCE_ALERT:
+			change = ChannelChange{Kind: t.kind, ID: t.alert.ID, Name: t.alert.Name, Before: t.alert.NotificationChannelIds}
+			change.After = t.edit.apply(change.Before)
+
+			if opts.DryRun || idsEqual(change.Before, change.After) {
+				return change
+			}
+
+			alert := *t.alert
+			alert.NotificationChannelIds = change.After
+			_, err = c.UpdateAlertWithContext(ctx, &Alert{Alert: alert})
+
+			if IsConflict(err) && attempt < maxRetries {
+				var latest *Alert
+				if latest, err = c.GetAlertWithContext(ctx, t.alert.ID); err == nil {
+					t.alert = &latest.Alert
+					continue
+				}
+			}
+		case RESOURCE_SILENCING_RULE:
+			change = ChannelChange{Kind: t.kind, ID: t.rule.ID, Name: t.rule.Name, Before: t.rule.NotificationChannelIds}
+			change.After = t.edit.apply(change.Before)
+
+			if opts.DryRun || idsEqual(change.Before, change.After) {
+				return change
+			}
+
+			rule := *t.rule
+			rule.NotificationChannelIds = change.After
+			_, err = c.UpdateSilencingRuleWithContext(ctx, &rule)
+
+			if IsConflict(err) && attempt < maxRetries {
+				if t.rule, err = c.GetSilencingRuleWithContext(ctx, t.rule.ID); err == nil {
+					continue
+				}
+			}
+		}
+
+		change.Err = err
+		change.Applied = err == nil
+
+		return change
+	}
+}
+
+func idsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// idsDifference returns IDs of a that are not in b
+func idsDifference(a, b []int) []int {
+	var res []int
+	for _, id := range a {
+		if !containsID(b, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
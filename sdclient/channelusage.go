@@ -0,0 +1,120 @@
+package sdclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ChannelUsage lists alerts and silencing rules referencing a notification channel
+type ChannelUsage struct {
+	ChannelID      int
+	Alerts         []*AlertItem
+	SilencingRules []SilencingRule
+}
+
+// InUse reports whether the notification channel is referenced by any alert or silencing rule
+func (u *ChannelUsage) InUse() bool {
+	return len(u.Alerts) > 0 || len(u.SilencingRules) > 0
+}
+
+// ChannelInUseError is returned when deleting a notification channel that is still referenced
+type ChannelInUseError struct {
+	Usage *ChannelUsage
+}
+
+func (e *ChannelInUseError) Error() string {
+	var refs []string
+
+	for _, a := range e.Usage.Alerts {
+		refs = append(refs, fmt.Sprintf("alert %d (%s)", a.ID, a.Name))
+	}
+
+	for _, r := range e.Usage.SilencingRules {
+		refs = append(refs, fmt.Sprintf("silencing rule %d (%s)", r.ID, r.Name))
+	}
+
+	return fmt.Sprintf("notification channel %d is referenced by %s", e.Usage.ChannelID, strings.Join(refs, ", "))
+}
+
+// ChannelUsage returns alerts and silencing rules referencing a notification channel
+func (c *Client) ChannelUsage(id int) (*ChannelUsage, error) {
+	return c.ChannelUsageWithContext(context.Background(), id)
+}
+
+// ChannelUsageWithContext returns alerts and silencing rules referencing a notification channel
+func (c *Client) ChannelUsageWithContext(ctx context.Context, id int) (*ChannelUsage, error) {
+
+	alerts, err := c.ListAlertsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &ChannelUsage{ChannelID: id}
+
+	for _, a := range alerts.Alerts {
+		if a != nil && containsID(a.NotificationChannelIds, id) {
+			usage.Alerts = append(usage.Alerts, a)
+		}
+	}
+
+	for _, r := range rules {
+		if containsID(r.NotificationChannelIds, id) {
+			usage.SilencingRules = append(usage.SilencingRules, r)
+		}
+	}
+
+	return usage, nil
+}
+
+// SafeDeleteOptions configures SafeDeleteNotificationChannel
+type SafeDeleteOptions struct {
+	// ReplacementID is a notification channel alerts are reassigned to before the channel is deleted
+	ReplacementID int
+}
+
+// SafeDeleteNotificationChannel deletes a notification channel unless it is still referenced
+func (c *Client) SafeDeleteNotificationChannel(id int, opts SafeDeleteOptions) error {
+	return c.SafeDeleteNotificationChannelWithContext(context.Background(), id, opts)
+}
+
+// SafeDeleteNotificationChannelWithContext deletes a notification channel unless it is still referenced.
+// With a replacement channel, alerts and silencing rules referencing the channel are reassigned to it first.
+func (c *Client) SafeDeleteNotificationChannelWithContext(ctx context.Context, id int, opts SafeDeleteOptions) error {
+
+	if opts.ReplacementID != 0 {
+		res, err := c.ReplaceNotificationChannelWithContext(ctx, id, opts.ReplacementID, ReplaceChannelOptions{})
+		if err != nil {
+			return err
+		}
+
+		if failed := res.Failed(); len(failed) > 0 {
+			return fmt.Errorf("reassigning %s %d: %w", failed[0].Kind, failed[0].ID, failed[0].Err)
+		}
+	}
+
+	usage, err := c.ChannelUsageWithContext(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if usage.InUse() {
+		return &ChannelInUseError{Usage: usage}
+	}
+
+	return c.DeleteNotificationChannelWithContext(ctx, id)
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package sdclient
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -13,6 +14,14 @@ type Client struct {
 	HTTPClient *http.Client
 	Endpoint   string
 	ApiKey     string
+	// ValidateChannels enables checking of notification channels referenced by alerts on create and update
+	ValidateChannels bool
+	// ChannelCacheTTL is how long the notification channel index is cached, DefaultChannelCacheTTL if zero
+	ChannelCacheTTL time.Duration
+	// SecretResolver resolves secret references in notification channel options, DefaultSecretResolver if nil
+	SecretResolver SecretResolver
+
+	channels channelCache
 }
 
 // New creates a new Sysdig Monitoring API client.
@@ -36,6 +45,18 @@ func (c *Client) WithAPIKey(apiKey string) *Client {
 	return c
 }
 
+// WithChannelValidation enables checking of notification channels referenced by alerts on create and update.
+func (c *Client) WithChannelValidation() *Client {
+	c.ValidateChannels = true
+	return c
+}
+
+// WithChannelCacheTTL sets how long the notification channel index is cached.
+func (c *Client) WithChannelCacheTTL(ttl time.Duration) *Client {
+	c.ChannelCacheTTL = ttl
+	return c
+}
+
 func (c *Client) sendRequest(req *http.Request, v interface{}) error {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.ApiKey))
 	req.Header.Set("Accept", "application/json")
@@ -47,10 +68,10 @@ func (c *Client) sendRequest(req *http.Request, v interface{}) error {
 	defer res.Body.Close()
 
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("request failed with status %d", res.StatusCode)
+		return &APIError{StatusCode: res.StatusCode}
 	}
 
-	if req.Method == http.MethodDelete {
+	if req.Method == http.MethodDelete || v == nil {
 		return nil
 	}
 
@@ -60,3 +81,29 @@ func (c *Client) sendRequest(req *http.Request, v interface{}) error {
 
 	return nil
 }
+
+// APIError is returned when the Sysdig Monitoring API responds with an error status.
+type APIError struct {
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("request failed with status %d", e.StatusCode)
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an API error with status 409, returned when a resource was modified concurrently.
+// Updates send the version of the resource and the API rejects stale versions with status 409,
+// update functions fetch the current version first when the version is not set.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
@@ -0,0 +1,117 @@
+package sdclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EffectiveSilence is a silencing rule suppressing notifications of an alert
+type EffectiveSilence struct {
+	Rule SilencingRule
+	// ChannelIDs lists notification channels of the alert silenced by the rule
+	ChannelIDs []int
+}
+
+// SilenceCheck is the outcome of EffectiveSilences
+type SilenceCheck struct {
+	At       time.Time
+	Silences []EffectiveSilence
+	// UnsilencedChannels lists notification channels of the alert no rule silences
+	UnsilencedChannels []int
+	// Unparsed lists rules in effect whose scope could not be parsed, they are not evaluated
+	Unparsed []SilencingRule
+}
+
+// Silenced reports whether every notification of the alert is suppressed
+func (c *SilenceCheck) Silenced() bool {
+	return len(c.Silences) > 0 && len(c.UnsilencedChannels) == 0
+}
+
+// EffectiveSilences returns silencing rules that suppress notifications of the alert for the label values at the given time
+func (c *Client) EffectiveSilences(alert *AlertItem, labels map[string]string, at time.Time) (*SilenceCheck, error) {
+	return c.EffectiveSilencesWithContext(context.Background(), alert, labels, at)
+}
+
+// EffectiveSilencesWithContext returns silencing rules that suppress notifications of the alert for the label values
+// at the given time. A rule applies when it is enabled, its window contains at, its scope matches the labels and
+// it shares a notification channel with the alert, a rule without channels silences all channels.
+// Rules of another team than the alert's are ignored.
+func (c *Client) EffectiveSilencesWithContext(ctx context.Context, alert *AlertItem, labels map[string]string, at time.Time) (*SilenceCheck, error) {
+
+	if alert == nil {
+		return nil, fmt.Errorf("alert is required")
+	}
+
+	rules, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkSilences(rules, alert, labels, at), nil
+}
+
+func checkSilences(rules []SilencingRule, alert *AlertItem, labels map[string]string, at time.Time) *SilenceCheck {
+	res := &SilenceCheck{At: at}
+
+	silenced := make(map[int]bool)
+	silencedAll := false
+
+	for _, r := range rules {
+		if !r.IsActive(at) {
+			continue
+		}
+
+		if r.TeamID != 0 && alert.TeamID != 0 && r.TeamID != alert.TeamID {
+			continue
+		}
+
+		channels := alert.NotificationChannelIds
+		if len(r.NotificationChannelIds) > 0 {
+			channels = idsIntersection(alert.NotificationChannelIds, r.NotificationChannelIds)
+			if len(channels) == 0 {
+				continue
+			}
+		}
+
+		scope, err := r.ParsedScope()
+		if err != nil {
+			res.Unparsed = append(res.Unparsed, r)
+			continue
+		}
+
+		if !scope.Matches(labels) {
+			continue
+		}
+
+		res.Silences = append(res.Silences, EffectiveSilence{Rule: r, ChannelIDs: channels})
+
+		if len(r.NotificationChannelIds) == 0 {
+			silencedAll = true
+		}
+		for _, id := range channels {
+			silenced[id] = true
+		}
+	}
+
+	if !silencedAll {
+		for _, id := range alert.NotificationChannelIds {
+			if !silenced[id] {
+				res.UnsilencedChannels = append(res.UnsilencedChannels, id)
+			}
+		}
+	}
+
+	return res
+}
+
+// idsIntersection returns IDs of a that are in b
+func idsIntersection(a, b []int) []int {
+	var res []int
+	for _, id := range a {
+		if containsID(b, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -6,12 +6,48 @@ const (
 	URI_ALERTS       = "/api/alerts"
 	URI_ALERTS_V2    = "/api/v2/alerts"
 	URI_SILENCERULES = "/api/v1/silencingRules"
+	URI_ALERT_EVENTS = "/api/notifications"
+	URI_USERS        = "/api/users"
 
 	ALERT_SERVERITY_LOW    = "low"
 	ALERT_SERVERITY_MEDIUM = "medium"
 	ALERT_SERVERITY_HIGH   = "high"
 	ALERT_TYPE_PROMETHEUS  = "PROMETHEUS"
 	ALERT_TYPE_MANUAL      = "MANUAL"
+
+	// ALERT_TIMESPAN_MIN is the shortest allowed alert timespan in microseconds
+	ALERT_TIMESPAN_MIN int64 = 60 * 1000 * 1000
+	// ALERT_TIMESPAN_MAX is the longest allowed alert timespan in microseconds
+	ALERT_TIMESPAN_MAX int64 = 24 * 60 * 60 * 1000 * 1000
+
+	ALERT_SEVERITY_MIN = 0
+	ALERT_SEVERITY_MAX = 7
+
+	ALERT_EVENT_STATE_ACTIVE = "ACTIVE"
+	ALERT_EVENT_STATE_OK     = "OK"
+
+	CHANNEL_TYPE_EMAIL                    = "EMAIL"
+	CHANNEL_TYPE_SLACK                    = "SLACK"
+	CHANNEL_TYPE_PAGER_DUTY               = "PAGER_DUTY"
+	CHANNEL_TYPE_WEBHOOK                  = "WEBHOOK"
+	CHANNEL_TYPE_OPSGENIE                 = "OPSGENIE"
+	CHANNEL_TYPE_VICTOROPS                = "VICTOROPS"
+	CHANNEL_TYPE_MS_TEAMS                 = "MS_TEAMS"
+	CHANNEL_TYPE_SNS                      = "SNS"
+	CHANNEL_TYPE_IBM_EVENT_NOTIFICATIONS  = "IBM_EVENT_NOTIFICATIONS"
+	CHANNEL_TYPE_PROMETHEUS_ALERT_MANAGER = "PROMETHEUS_ALERT_MANAGER"
+
+	ROLE_TEAM_READ     = "ROLE_TEAM_READ"
+	ROLE_TEAM_STANDARD = "ROLE_TEAM_STANDARD"
+	ROLE_TEAM_EDIT     = "ROLE_TEAM_EDIT"
+	ROLE_TEAM_MANAGER  = "ROLE_TEAM_MANAGER"
+
+	SCOPE_OP_EQ          = "="
+	SCOPE_OP_NOT_EQ      = "!="
+	SCOPE_OP_IN          = "in"
+	SCOPE_OP_NOT_IN      = "not in"
+	SCOPE_OP_CONTAINS    = "contains"
+	SCOPE_OP_STARTS_WITH = "starts with"
 )
 
 var Regions = map[string]string{
@@ -26,3 +62,10 @@ var Regions = map[string]string{
 	"br-sao":   "https://br-sao.monitoring.cloud.ibm.com",
 	"eu-es":    "https://eu-es.monitoring.cloud.ibm.com",
 }
+
+const (
+	RESOURCE_ALERT                = "alert"
+	RESOURCE_NOTIFICATION_CHANNEL = "notificationChannel"
+	RESOURCE_SILENCING_RULE       = "silencingRule"
+	RESOURCE_TEAM                 = "team"
+)
@@ -0,0 +1,298 @@
+package sdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes occurrences recurring at a wall clock time on selected weekdays in a time zone.
+// Occurrences are computed in the wall clock of the location so they keep their local time across DST changes,
+// a time skipped by a DST change is moved forward by the length of the gap.
+type Schedule struct {
+	// Weekdays the occurrences are on, every day if empty
+	Weekdays []time.Weekday
+	Hour     int
+	Minute   int
+	// Location is the time zone of the wall clock time, UTC if nil
+	Location *time.Location
+}
+
+// ParseSchedule parses a cron like "minute hour * * weekdays" expression, e.g. "0 2 * * 0" for every Sunday at 02:00.
+// Weekdays are 0-6 starting on Sunday, lists (1,3,5), ranges (1-5) and * are supported.
+func ParseSchedule(expr string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("schedule %q must have 5 fields", expr)
+	}
+
+	if fields[2] != "*" || fields[3] != "*" {
+		return Schedule{}, fmt.Errorf("schedule %q: day of month and month must be *", expr)
+	}
+
+	minute, err := strconv.Atoi(fields[0])
+	if err != nil || minute < 0 || minute > 59 {
+		return Schedule{}, fmt.Errorf("schedule %q: invalid minute %q", expr, fields[0])
+	}
+
+	hour, err := strconv.Atoi(fields[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return Schedule{}, fmt.Errorf("schedule %q: invalid hour %q", expr, fields[1])
+	}
+
+	s := Schedule{Hour: hour, Minute: minute, Location: loc}
+
+	if fields[4] == "*" {
+		return s, nil
+	}
+
+	for _, part := range strings.Split(fields[4], ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || first < 0 || last > 6 || first > last {
+			return Schedule{}, fmt.Errorf("schedule %q: invalid weekdays %q", expr, part)
+		}
+
+		for d := first; d <= last; d++ {
+			s.Weekdays = append(s.Weekdays, time.Weekday(d))
+		}
+	}
+
+	return s, nil
+}
+
+// Next returns the first occurrence after t
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := s.location()
+	local := t.In(loc)
+
+	// a week and a day covers every weekday even when today's occurrence already passed
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		at := time.Date(day.Year(), day.Month(), day.Day(), s.Hour, s.Minute, 0, 0, loc)
+
+		if at.After(t) && s.onWeekday(at.Weekday()) {
+			return at
+		}
+	}
+
+	return time.Time{}
+}
+
+// Occurrences returns the next n occurrences after t
+func (s Schedule) Occurrences(t time.Time, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+
+	res := make([]time.Time, 0, n)
+
+	for len(res) < n {
+		next := s.Next(t)
+		if next.IsZero() {
+			break
+		}
+		res = append(res, next)
+		t = next
+	}
+
+	return res
+}
+
+func (s Schedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+func (s Schedule) onWeekday(d time.Weekday) bool {
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+	for _, w := range s.Weekdays {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceWindow is a recurring silence materialized as one silencing rule per occurrence.
+// Rules of a window are named "<NamePrefix> <RFC3339 start>" and identified by that exact form.
+type MaintenanceWindow struct {
+	NamePrefix             string
+	Scope                  string
+	Schedule               Schedule
+	Duration               time.Duration
+	NotificationChannelIds []int
+	TeamID                 int
+}
+
+// Rules returns silencing rules of the next n occurrences, including an occurrence in progress at now
+func (w MaintenanceWindow) Rules(now time.Time, n int) ([]SilencingRule, error) {
+	if strings.TrimSpace(w.NamePrefix) == "" {
+		return nil, errors.New("maintenance window name prefix is required")
+	}
+
+	if w.Duration <= 0 {
+		return nil, fmt.Errorf("maintenance window duration must be positive, got %s", w.Duration)
+	}
+
+	if n < 0 {
+		return nil, fmt.Errorf("number of occurrences must not be negative, got %d", n)
+	}
+
+	rules := make([]SilencingRule, 0, n)
+
+	for _, start := range w.Schedule.Occurrences(now.Add(-w.Duration), n+1) {
+		if len(rules) == n {
+			break
+		}
+
+		rule := SilencingRule{
+			Name:                   w.ruleName(start),
+			Enabled:                true,
+			Scope:                  w.Scope,
+			NotificationChannelIds: w.NotificationChannelIds,
+			TeamID:                 w.TeamID,
+		}
+		rule.SetWindow(start, w.Duration)
+
+		if rule.End().After(now) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func (w MaintenanceWindow) ruleName(start time.Time) string {
+	return fmt.Sprintf("%s %s", w.NamePrefix, start.In(w.Schedule.location()).Format(time.RFC3339))
+}
+
+// owns reports whether the rule name has the "<prefix> <RFC3339 start>" form of rules created for the window
+func (w MaintenanceWindow) owns(rule SilencingRule) bool {
+	prefix := w.NamePrefix + " "
+	if !strings.HasPrefix(rule.Name, prefix) {
+		return false
+	}
+
+	_, err := time.Parse(time.RFC3339, strings.TrimPrefix(rule.Name, prefix))
+	return err == nil
+}
+
+// MaintenanceSyncResult describes the outcome of SyncMaintenanceWindow
+type MaintenanceSyncResult struct {
+	Created []SilencingRule
+	Updated []SilencingRule
+	Kept    []SilencingRule
+	Pruned  *BulkResult
+	// Errors lists occurrences that could not be created or updated
+	Errors []error
+}
+
+// SyncMaintenanceWindow keeps silencing rules of the next n occurrences of the window created
+func (c *Client) SyncMaintenanceWindow(w MaintenanceWindow, n int) (*MaintenanceSyncResult, error) {
+	return c.SyncMaintenanceWindowWithContext(context.Background(), w, n)
+}
+
+// SyncMaintenanceWindowWithContext keeps silencing rules of the next n occurrences of the window created.
+// Rules of the window that differ from the schedule are updated, rules of past or no longer scheduled
+// occurrences are deleted. A rule silencing at the moment is never deleted.
+func (c *Client) SyncMaintenanceWindowWithContext(ctx context.Context, w MaintenanceWindow, n int) (*MaintenanceSyncResult, error) {
+
+	now := time.Now()
+
+	desired, err := w.Rules(now, n)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]SilencingRule)
+	var stale []int
+
+	for _, r := range existing {
+		if !w.owns(r) {
+			continue
+		}
+
+		if _, dup := owned[r.Name]; dup {
+			stale = append(stale, r.ID)
+			continue
+		}
+
+		owned[r.Name] = r
+	}
+
+	res := &MaintenanceSyncResult{}
+
+	for _, want := range desired {
+		have, ok := owned[want.Name]
+		if !ok {
+			created, err := c.CreateSilencingRuleWithContext(ctx, &want)
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Errorf("creating %q: %w", want.Name, err))
+				continue
+			}
+			res.Created = append(res.Created, *created)
+			continue
+		}
+
+		delete(owned, want.Name)
+
+		if maintenanceRuleMatches(have, want) {
+			res.Kept = append(res.Kept, have)
+			continue
+		}
+
+		want.ID = have.ID
+		want.Version = have.Version
+
+		updated, err := c.UpdateSilencingRuleWithContext(ctx, &want)
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("updating %q: %w", want.Name, err))
+			continue
+		}
+		res.Updated = append(res.Updated, *updated)
+	}
+
+	for _, r := range owned {
+		if r.IsActive(now) {
+			res.Kept = append(res.Kept, r)
+			continue
+		}
+		stale = append(stale, r.ID)
+	}
+
+	if len(stale) > 0 {
+		res.Pruned, err = c.DeleteSilencingRulesWithContext(ctx, stale)
+		if err != nil {
+			res.Errors = append(res.Errors, err)
+		}
+	}
+
+	return res, nil
+}
+
+func maintenanceRuleMatches(have, want SilencingRule) bool {
+	return have.Enabled == want.Enabled &&
+		have.StartTs == want.StartTs &&
+		have.DurationInSec == want.DurationInSec &&
+		have.Scope == want.Scope &&
+		have.TeamID == want.TeamID &&
+		idsEqual(have.NotificationChannelIds, want.NotificationChannelIds)
+}
@@ -3,9 +3,14 @@ package sdclient
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"regexp"
+	"time"
 )
 
 // NotificationChannel represents a notification channel request/response object
@@ -21,6 +26,10 @@ type NotificationChannels struct {
 // NotificationChannelOptions is interface for various possible notification channel type
 type NotificationChannelOptions interface {
 	String() string
+	// ChannelType returns the notification channel type the options belong to
+	ChannelType() string
+	// Validate checks the options before they are sent to the API
+	Validate() error
 }
 
 // NotificationChannelItem represents a single notification channel
@@ -31,7 +40,7 @@ type NotificationChannelItem struct {
 	ModifiedOn           int64            `json:"modifiedOn,omitempty"`
 	TeamID               int              `json:"teamId,omitempty"`
 	Type                 string           `json:"type,omitempty"`
-	Enabled              bool             `json:"enabled,omitempty"`
+	Enabled              bool             `json:"enabled"`
 	SendTestNotification bool             `json:"sendTestNotification,omitempty"`
 	Name                 string           `json:"name,omitempty"`
 	SettingsID           int              `json:"settingsId,omitempty"`
@@ -40,8 +49,8 @@ type NotificationChannelItem struct {
 
 // EmailNotificationChannel represents options for an email notification channel
 type EmailNotificationChannelOptions struct {
-	NotifyOnResolve *bool     `json:"notifyOnResolve,omitempty"`
-	NotifyOnOk      *bool     `json:"notifyOnOk,omitempty"`
+	NotifyOnResolve *bool    `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool    `json:"notifyOnOk,omitempty"`
 	EmailRecipients []string `json:"emailRecipients,omitempty"`
 }
 
@@ -49,12 +58,17 @@ func (nc *EmailNotificationChannelOptions) String() string {
 	return fmt.Sprintf("email recipients: %v", nc.EmailRecipients)
 }
 
+// ChannelType returns the notification channel type of the options
+func (nc *EmailNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_EMAIL
+}
+
 // PagerDutyNotificationChannelOptions represents options for a PagerDuty notification channel
 type PagerDutyNotificationChannelOptions struct {
-	NotifyOnResolve *bool   `json:"notifyOnResolve,omitempty"`
-	NotifyOnOk      *bool   `json:"notifyOnOk,omitempty"`
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
 	Account         string `json:"account,omitempty"`
-	ServiceKey      string `json:"serviceKey,omitempty"`
+	ServiceKey      Secret `json:"serviceKey,omitempty"`
 	ServiceName     string `json:"serviceName,omitempty"`
 }
 
@@ -62,19 +76,218 @@ func (nc *PagerDutyNotificationChannelOptions) String() string {
 	return fmt.Sprintf("account: %s, service key: %s, service name: %s", nc.Account, nc.ServiceKey, nc.ServiceName)
 }
 
+// ChannelType returns the notification channel type of the options
+func (nc *PagerDutyNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_PAGER_DUTY
+}
+
 // SlackNotificationChannelOptions represents options for a Slack notification channel
 type SlackNotificationChannelOptions struct {
-	NotifyOnResolve *bool   `json:"notifyOnResolve,omitempty"`
-	NotifyOnOk      *bool   `json:"notifyOnOk,omitempty"`
-	NotifyOnAck     *bool     `json:"notifyOnAck,omitempty"`
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
+	NotifyOnAck     *bool  `json:"notifyOnAck,omitempty"`
 	Channel         string `json:"channel,omitempty"`
-	URL             string `json:"url,omitempty"`
+	URL             Secret `json:"url,omitempty"`
 }
 
 func (nc *SlackNotificationChannelOptions) String() string {
 	return fmt.Sprintf("channel: %s, url: %s", nc.Channel, nc.URL)
 }
 
+// ChannelType returns the notification channel type of the options
+func (nc *SlackNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_SLACK
+}
+
+// WebhookNotificationChannelOptions represents options for a generic webhook notification channel
+type WebhookNotificationChannelOptions struct {
+	NotifyOnResolve          *bool                  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk               *bool                  `json:"notifyOnOk,omitempty"`
+	URL                      string                 `json:"url,omitempty"`
+	AllowInsecureConnections *bool                  `json:"allowInsecureConnections,omitempty"`
+	AdditionalHeaders        map[string]Secret      `json:"additionalHeaders,omitempty"`
+	CustomData               map[string]interface{} `json:"customData,omitempty"`
+}
+
+func (nc *WebhookNotificationChannelOptions) String() string {
+	return fmt.Sprintf("url: %s, additional headers: %d, custom data: %d", nc.URL, len(nc.AdditionalHeaders), len(nc.CustomData))
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *WebhookNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_WEBHOOK
+}
+
+// SetBasicAuth sets the Authorization header used by the webhook to basic authentication
+func (nc *WebhookNotificationChannelOptions) SetBasicAuth(username, password string) {
+	if nc.AdditionalHeaders == nil {
+		nc.AdditionalHeaders = make(map[string]Secret)
+	}
+	nc.AdditionalHeaders["Authorization"] = Secret("Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+}
+
+// OpsGenieNotificationChannelOptions represents options for an OpsGenie notification channel
+type OpsGenieNotificationChannelOptions struct {
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
+	APIKey          Secret `json:"apiKey,omitempty"`
+	Region          string `json:"region,omitempty"`
+}
+
+func (nc *OpsGenieNotificationChannelOptions) String() string {
+	return fmt.Sprintf("api key: %s, region: %s", nc.APIKey, nc.Region)
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *OpsGenieNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_OPSGENIE
+}
+
+// VictorOpsNotificationChannelOptions represents options for a VictorOps (Splunk On-Call) notification channel
+type VictorOpsNotificationChannelOptions struct {
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
+	APIKey          Secret `json:"apiKey,omitempty"`
+	RoutingKey      string `json:"routingKey,omitempty"`
+}
+
+func (nc *VictorOpsNotificationChannelOptions) String() string {
+	return fmt.Sprintf("api key: %s, routing key: %s", nc.APIKey, nc.RoutingKey)
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *VictorOpsNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_VICTOROPS
+}
+
+// MSTeamsNotificationChannelOptions represents options for a Microsoft Teams notification channel
+type MSTeamsNotificationChannelOptions struct {
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
+	URL             Secret `json:"url,omitempty"`
+}
+
+func (nc *MSTeamsNotificationChannelOptions) String() string {
+	return fmt.Sprintf("url: %s", nc.URL)
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *MSTeamsNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_MS_TEAMS
+}
+
+// SNSNotificationChannelOptions represents options for an Amazon SNS notification channel
+type SNSNotificationChannelOptions struct {
+	NotifyOnResolve *bool    `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool    `json:"notifyOnOk,omitempty"`
+	SnsTopicARNs    []string `json:"snsTopicARNs,omitempty"`
+}
+
+func (nc *SNSNotificationChannelOptions) String() string {
+	return fmt.Sprintf("topics: %v", nc.SnsTopicARNs)
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *SNSNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_SNS
+}
+
+// IBMEventNotificationsNotificationChannelOptions represents options for an IBM Cloud Event Notifications notification channel
+type IBMEventNotificationsNotificationChannelOptions struct {
+	NotifyOnResolve *bool  `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk      *bool  `json:"notifyOnOk,omitempty"`
+	InstanceID      string `json:"instanceId,omitempty"`
+}
+
+func (nc *IBMEventNotificationsNotificationChannelOptions) String() string {
+	return fmt.Sprintf("instance id: %s", nc.InstanceID)
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *IBMEventNotificationsNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_IBM_EVENT_NOTIFICATIONS
+}
+
+// PrometheusAlertManagerNotificationChannelOptions represents options for a Prometheus Alertmanager notification channel
+type PrometheusAlertManagerNotificationChannelOptions struct {
+	NotifyOnResolve          *bool             `json:"notifyOnResolve,omitempty"`
+	NotifyOnOk               *bool             `json:"notifyOnOk,omitempty"`
+	URL                      string            `json:"url,omitempty"`
+	AllowInsecureConnections *bool             `json:"allowInsecureConnections,omitempty"`
+	AdditionalHeaders        map[string]Secret `json:"additionalHeaders,omitempty"`
+}
+
+func (nc *PrometheusAlertManagerNotificationChannelOptions) String() string {
+	return fmt.Sprintf("url: %s, additional headers: %d", nc.URL, len(nc.AdditionalHeaders))
+}
+
+// ChannelType returns the notification channel type of the options
+func (nc *PrometheusAlertManagerNotificationChannelOptions) ChannelType() string {
+	return CHANNEL_TYPE_PROMETHEUS_ALERT_MANAGER
+}
+
+// RawNotificationChannelOptions holds options of an unknown notification channel type
+type RawNotificationChannelOptions map[string]interface{}
+
+func (nc RawNotificationChannelOptions) String() string {
+	return fmt.Sprintf("options: %v", map[string]interface{}(nc))
+}
+
+// ChannelType returns an empty string as raw options can belong to any channel type
+func (nc RawNotificationChannelOptions) ChannelType() string {
+	return ""
+}
+
+// Validate does not check raw options
+func (nc RawNotificationChannelOptions) Validate() error {
+	return nil
+}
+
+// DecodeOptions decodes channel options into the options struct matching the channel type,
+// options of unknown channel types are returned as RawNotificationChannelOptions
+func (nc NotificationChannelItem) DecodeOptions() (NotificationChannelOptions, error) {
+	if nc.Options == nil {
+		return nil, fmt.Errorf("notification channel %q has no options", nc.Name)
+	}
+
+	var opts NotificationChannelOptions
+
+	switch nc.Type {
+	case CHANNEL_TYPE_EMAIL:
+		opts = new(EmailNotificationChannelOptions)
+	case CHANNEL_TYPE_SLACK:
+		opts = new(SlackNotificationChannelOptions)
+	case CHANNEL_TYPE_PAGER_DUTY:
+		opts = new(PagerDutyNotificationChannelOptions)
+	case CHANNEL_TYPE_WEBHOOK:
+		opts = new(WebhookNotificationChannelOptions)
+	case CHANNEL_TYPE_OPSGENIE:
+		opts = new(OpsGenieNotificationChannelOptions)
+	case CHANNEL_TYPE_VICTOROPS:
+		opts = new(VictorOpsNotificationChannelOptions)
+	case CHANNEL_TYPE_MS_TEAMS:
+		opts = new(MSTeamsNotificationChannelOptions)
+	case CHANNEL_TYPE_SNS:
+		opts = new(SNSNotificationChannelOptions)
+	case CHANNEL_TYPE_IBM_EVENT_NOTIFICATIONS:
+		opts = new(IBMEventNotificationsNotificationChannelOptions)
+	case CHANNEL_TYPE_PROMETHEUS_ALERT_MANAGER:
+		opts = new(PrometheusAlertManagerNotificationChannelOptions)
+	default:
+		raw := make(RawNotificationChannelOptions)
+		if err := json.Unmarshal(*nc.Options, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	if err := json.Unmarshal(*nc.Options, opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
 // ListNotificationChannels returns a list of all notification channels
 func (c *Client) ListNotificationChannels() (*NotificationChannels, error) {
 	return c.ListNotificationChannelsWithContext(context.Background())
@@ -123,6 +336,90 @@ func (c *Client) GetNotificationChannelWithContext(ctx context.Context, id int)
 	return res, nil
 }
 
+// NotificationChannelFilter selects notification channels, zero values are not filtered on
+type NotificationChannelFilter struct {
+	// Name matches the channel name exactly
+	Name string
+	// NamePattern matches the channel name with a regular expression
+	NamePattern *regexp.Regexp
+	Type        string
+	TeamID      int
+	Enabled     *bool
+}
+
+// Match reports whether the notification channel matches the filter
+func (f NotificationChannelFilter) Match(ch NotificationChannelItem) bool {
+	if f.Name != "" && ch.Name != f.Name {
+		return false
+	}
+	if f.NamePattern != nil && !f.NamePattern.MatchString(ch.Name) {
+		return false
+	}
+	if f.Type != "" && ch.Type != f.Type {
+		return false
+	}
+	if f.TeamID != 0 && ch.TeamID != f.TeamID {
+		return false
+	}
+	if f.Enabled != nil && ch.Enabled != *f.Enabled {
+		return false
+	}
+	return true
+}
+
+// Filter returns notification channels matching the filter
+func (nc *NotificationChannels) Filter(filter NotificationChannelFilter) []NotificationChannelItem {
+	var res []NotificationChannelItem
+	for _, ch := range nc.NotificationChannels {
+		if filter.Match(ch) {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+// FindNotificationChannels returns notification channels matching the filter
+func (c *Client) FindNotificationChannels(filter NotificationChannelFilter) ([]NotificationChannelItem, error) {
+	return c.FindNotificationChannelsWithContext(context.Background(), filter)
+}
+
+// FindNotificationChannelsWithContext returns notification channels matching the filter
+func (c *Client) FindNotificationChannelsWithContext(ctx context.Context, filter NotificationChannelFilter) ([]NotificationChannelItem, error) {
+	channels, err := c.ListNotificationChannelsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return channels.Filter(filter), nil
+}
+
+// GetNotificationChannelByName returns a single notification channel by name
+func (c *Client) GetNotificationChannelByName(name string) (*NotificationChannel, error) {
+	return c.GetNotificationChannelByNameWithContext(context.Background(), name)
+}
+
+// GetNotificationChannelByNameWithContext returns a single notification channel by name,
+// it fails when no channel or more than one channel has the name
+func (c *Client) GetNotificationChannelByNameWithContext(ctx context.Context, name string) (*NotificationChannel, error) {
+	matches, err := c.FindNotificationChannelsWithContext(ctx, NotificationChannelFilter{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("notification channel %q not found", name)
+	case 1:
+		return &NotificationChannel{NotificationChannel: matches[0]}, nil
+	default:
+		ids := make([]int, 0, len(matches))
+		for _, ch := range matches {
+			ids = append(ids, ch.ID)
+		}
+		return nil, fmt.Errorf("notification channel name %q is ambiguous, matches channels %v", name, ids)
+	}
+}
+
 // CreateNotificationChannel creates an notification channel
 func (c *Client) CreateNotificationChannel(channel *NotificationChannel) (*NotificationChannel, error) {
 	return c.CreateNotificationChannelWithContext(context.Background(), channel)
@@ -133,7 +430,12 @@ func (c *Client) CreateNotificationChannelWithContext(ctx context.Context, chann
 
 	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_CHANNELS)
 
-	body, err := json.Marshal(channel)
+	item, err := c.resolveSecrets(channel.NotificationChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(&NotificationChannel{NotificationChannel: item})
 	if err != nil {
 		return nil, err
 	}
@@ -151,9 +453,159 @@ func (c *Client) CreateNotificationChannelWithContext(ctx context.Context, chann
 		return nil, err
 	}
 
+	c.InvalidateChannelCache()
+
+	return res, nil
+}
+
+// UpdateNotificationChannel updates an notification channel
+func (c *Client) UpdateNotificationChannel(channel *NotificationChannel) (*NotificationChannel, error) {
+	return c.UpdateNotificationChannelWithContext(context.Background(), channel)
+}
+
+// UpdateNotificationChannelWithContext updates an notification channel, see IsConflict for version handling
+func (c *Client) UpdateNotificationChannelWithContext(ctx context.Context, channel *NotificationChannel) (*NotificationChannel, error) {
+
+	if channel.NotificationChannel.Version == 0 {
+		current, err := c.GetNotificationChannelWithContext(ctx, channel.NotificationChannel.ID)
+		if err != nil {
+			return nil, err
+		}
+		channel.NotificationChannel.Version = current.NotificationChannel.Version
+	}
+
+	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_CHANNELS, channel.NotificationChannel.ID)
+
+	item, err := c.resolveSecrets(channel.NotificationChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(&NotificationChannel{NotificationChannel: item})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res = new(NotificationChannel)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	c.InvalidateChannelCache()
+
+	return res, nil
+}
+
+// EnableNotificationChannel enables an notification channel
+func (c *Client) EnableNotificationChannel(id int) (*NotificationChannel, error) {
+	return c.EnableNotificationChannelWithContext(context.Background(), id)
+}
+
+// EnableNotificationChannelWithContext enables an notification channel keeping all other settings
+func (c *Client) EnableNotificationChannelWithContext(ctx context.Context, id int) (*NotificationChannel, error) {
+	return c.setNotificationChannelEnabled(ctx, id, true)
+}
+
+// DisableNotificationChannel disables an notification channel
+func (c *Client) DisableNotificationChannel(id int) (*NotificationChannel, error) {
+	return c.DisableNotificationChannelWithContext(context.Background(), id)
+}
+
+// DisableNotificationChannelWithContext disables an notification channel keeping all other settings
+func (c *Client) DisableNotificationChannelWithContext(ctx context.Context, id int) (*NotificationChannel, error) {
+	return c.setNotificationChannelEnabled(ctx, id, false)
+}
+
+func (c *Client) setNotificationChannelEnabled(ctx context.Context, id int, enabled bool) (*NotificationChannel, error) {
+	channel, err := c.GetNotificationChannelWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel.NotificationChannel.Enabled == enabled {
+		return channel, nil
+	}
+
+	channel.NotificationChannel.Enabled = enabled
+	channel.NotificationChannel.SendTestNotification = false
+
+	return c.UpdateNotificationChannelWithContext(ctx, channel)
+}
+
+// TestNotificationResult describes the outcome of a test notification sent through a channel
+type TestNotificationResult struct {
+	ChannelID   int
+	ChannelName string
+	ChannelType string
+	SentAt      time.Time
+	// Accepted is true when Sysdig accepted the request to send the test notification.
+	// Delivery happens asynchronously and is not confirmed by the API.
+	Accepted bool
+	// Err is the reason Sysdig rejected the test notification
+	Err error
+}
+
+// TestNotificationChannel sends a test notification through an existing notification channel
+func (c *Client) TestNotificationChannel(id int) (*TestNotificationResult, error) {
+	return c.TestNotificationChannelWithContext(context.Background(), id)
+}
+
+// TestNotificationChannelWithContext sends a test notification through an existing notification channel.
+// A disabled channel or a test notification rejected by Sysdig is reported in the result. Errors reading
+// the channel, authentication failures, version conflicts, missing channels and server errors are returned.
+func (c *Client) TestNotificationChannelWithContext(ctx context.Context, id int) (*TestNotificationResult, error) {
+	channel, err := c.GetNotificationChannelWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TestNotificationResult{
+		ChannelID:   channel.NotificationChannel.ID,
+		ChannelName: channel.NotificationChannel.Name,
+		ChannelType: channel.NotificationChannel.Type,
+		SentAt:      time.Now(),
+	}
+
+	if !channel.NotificationChannel.Enabled {
+		res.Err = fmt.Errorf("notification channel %d is disabled", id)
+		return res, nil
+	}
+
+	channel.NotificationChannel.SendTestNotification = true
+
+	if _, err := c.UpdateNotificationChannelWithContext(ctx, channel); err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !rejectedTestNotification(apiErr.StatusCode) {
+			return nil, err
+		}
+
+		res.Err = err
+		return res, nil
+	}
+
+	res.Accepted = true
+
 	return res, nil
 }
 
+// rejectedTestNotification reports whether a status is Sysdig rejecting the test notification itself
+// rather than a failure of the request
+func rejectedTestNotification(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict:
+		return false
+	}
+	return status >= http.StatusBadRequest && status < http.StatusInternalServerError
+}
+
 // DeleteNotificationChannel deletes an notification channel
 func (c *Client) DeleteNotificationChannel(id int) error {
 	return c.DeleteNotificationChannelWithContext(context.Background(), id)
@@ -173,11 +625,28 @@ func (c *Client) DeleteNotificationChannelWithContext(ctx context.Context, id in
 		return err
 	}
 
+	c.InvalidateChannelCache()
+
 	return nil
 }
 
-// NewNotificationChannel creates a new notification channel of type channelType and provided options
+// NewNotificationChannel creates a new notification channel of type channelType and provided options.
+// Options implementing NotificationChannelOptions must belong to channelType and are validated.
 func NewNotificationChannel(channelName, channelType string, options interface{}) (*NotificationChannel, error) {
+	if opts, ok := asNotificationChannelOptions(options); ok {
+		if v := reflect.ValueOf(options); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, fmt.Errorf("options for %s notification channel are nil", channelType)
+		}
+
+		if t := opts.ChannelType(); t != "" && t != channelType {
+			return nil, fmt.Errorf("options for %s notification channel used for %s notification channel", t, channelType)
+		}
+
+		if err := opts.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	byteOptions, err := json.Marshal(options)
 	if err != nil {
 		return nil, err
@@ -195,3 +664,78 @@ func NewNotificationChannel(channelName, channelType string, options interface{}
 		},
 	}, nil
 }
+
+// WithTestNotification makes the API send a test notification when the channel is created or updated
+func (nc *NotificationChannel) WithTestNotification() *NotificationChannel {
+	nc.NotificationChannel.SendTestNotification = true
+	return nc
+}
+
+// NewEmailChannel creates a new email notification channel
+func NewEmailChannel(channelName string, options *EmailNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_EMAIL, options)
+}
+
+// NewSlackChannel creates a new Slack notification channel
+func NewSlackChannel(channelName string, options *SlackNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_SLACK, options)
+}
+
+// NewPagerDutyChannel creates a new PagerDuty notification channel
+func NewPagerDutyChannel(channelName string, options *PagerDutyNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_PAGER_DUTY, options)
+}
+
+// NewWebhookChannel creates a new webhook notification channel
+func NewWebhookChannel(channelName string, options *WebhookNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_WEBHOOK, options)
+}
+
+// NewOpsGenieChannel creates a new OpsGenie notification channel
+func NewOpsGenieChannel(channelName string, options *OpsGenieNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_OPSGENIE, options)
+}
+
+// NewVictorOpsChannel creates a new VictorOps notification channel
+func NewVictorOpsChannel(channelName string, options *VictorOpsNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_VICTOROPS, options)
+}
+
+// NewMSTeamsChannel creates a new Microsoft Teams notification channel
+func NewMSTeamsChannel(channelName string, options *MSTeamsNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_MS_TEAMS, options)
+}
+
+// NewSNSChannel creates a new Amazon SNS notification channel
+func NewSNSChannel(channelName string, options *SNSNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_SNS, options)
+}
+
+// NewIBMEventNotificationsChannel creates a new IBM Cloud Event Notifications notification channel
+func NewIBMEventNotificationsChannel(channelName string, options *IBMEventNotificationsNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_IBM_EVENT_NOTIFICATIONS, options)
+}
+
+// NewPrometheusAlertManagerChannel creates a new Prometheus Alertmanager notification channel
+func NewPrometheusAlertManagerChannel(channelName string, options *PrometheusAlertManagerNotificationChannelOptions) (*NotificationChannel, error) {
+	return NewNotificationChannel(channelName, CHANNEL_TYPE_PROMETHEUS_ALERT_MANAGER, options)
+}
+
+// asNotificationChannelOptions returns options as NotificationChannelOptions,
+// options passed by value are addressed as the interface is implemented on pointers
+func asNotificationChannelOptions(options interface{}) (NotificationChannelOptions, bool) {
+	if opts, ok := options.(NotificationChannelOptions); ok {
+		return opts, true
+	}
+
+	v := reflect.ValueOf(options)
+	if !v.IsValid() || v.Kind() == reflect.Ptr {
+		return nil, false
+	}
+
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+
+	opts, ok := p.Interface().(NotificationChannelOptions)
+	return opts, ok
+}
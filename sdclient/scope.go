@@ -0,0 +1,591 @@
+package sdclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ScopeCondition is a single condition of a scope, e.g. kubernetes.namespace.name in ("a", "b")
+type ScopeCondition struct {
+	Label  string
+	Op     string
+	Values []string
+}
+
+// Scope is a conjunction of conditions as used by silencing rule scopes, alert filters and team filters.
+// An empty scope matches everything.
+type Scope []ScopeCondition
+
+// Eq returns a condition matching label equal to value
+func Eq(label, value string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_EQ, Values: []string{value}}
+}
+
+// NotEq returns a condition matching label not equal to value
+func NotEq(label, value string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_NOT_EQ, Values: []string{value}}
+}
+
+// In returns a condition matching label equal to any of values
+func In(label string, values ...string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_IN, Values: values}
+}
+
+// NotIn returns a condition matching label equal to none of values
+func NotIn(label string, values ...string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_NOT_IN, Values: values}
+}
+
+// Contains returns a condition matching label containing value
+func Contains(label, value string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_CONTAINS, Values: []string{value}}
+}
+
+// StartsWith returns a condition matching label starting with value
+func StartsWith(label, value string) ScopeCondition {
+	return ScopeCondition{Label: label, Op: SCOPE_OP_STARTS_WITH, Values: []string{value}}
+}
+
+// And returns a scope matching all conditions
+func And(conds ...ScopeCondition) Scope {
+	return Scope(conds)
+}
+
+// Validate reports conditions that cannot be rendered as a valid scope: a label that is empty or not a single word,
+// an unknown operator, an in or not in without values and other operators without exactly one value.
+// Equality operators with several values are valid, they are rendered as in and not in.
+func (c ScopeCondition) Validate() error {
+	if c.Label == "" {
+		return errors.New("scope condition label is empty")
+	}
+
+	if strings.IndexFunc(c.Label, isScopeDelimiter) >= 0 {
+		return fmt.Errorf("scope condition label %q must not contain spaces, quotes or operators", c.Label)
+	}
+
+	switch c.Op {
+	case SCOPE_OP_IN, SCOPE_OP_NOT_IN:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("scope condition %s %s has no values", c.Label, c.Op)
+		}
+	case SCOPE_OP_EQ, SCOPE_OP_NOT_EQ:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("scope condition %s %s has no value", c.Label, c.Op)
+		}
+	case SCOPE_OP_CONTAINS, SCOPE_OP_STARTS_WITH:
+		if len(c.Values) != 1 {
+			return fmt.Errorf("scope condition %s %s must have exactly one value, got %d", c.Label, c.Op, len(c.Values))
+		}
+	default:
+		return fmt.Errorf("scope condition %s has unknown operator %q", c.Label, c.Op)
+	}
+
+	return nil
+}
+
+// Validate reports the first invalid condition of the scope
+func (s Scope) Validate() error {
+	for i, c := range s {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// String renders the condition with values quoted and escaped.
+// = and != with several values are rendered as in and not in, the output of an invalid condition
+// is not accepted by ParseScope, see Validate.
+func (c ScopeCondition) String() string {
+	quoted := make([]string, len(c.Values))
+	for i, v := range c.Values {
+		quoted[i] = quoteScopeValue(v)
+	}
+
+	op := c.Op
+	if len(c.Values) > 1 {
+		switch op {
+		case SCOPE_OP_EQ:
+			op = SCOPE_OP_IN
+		case SCOPE_OP_NOT_EQ:
+			op = SCOPE_OP_NOT_IN
+		}
+	}
+
+	switch op {
+	case SCOPE_OP_IN, SCOPE_OP_NOT_IN:
+		return fmt.Sprintf("%s %s (%s)", c.Label, op, strings.Join(quoted, ", "))
+	default:
+		return fmt.Sprintf("%s %s %s", c.Label, op, strings.Join(quoted, ", "))
+	}
+}
+
+// String renders the scope as accepted by the API, see Validate
+func (s Scope) String() string {
+	conds := make([]string, len(s))
+	for i, c := range s {
+		conds[i] = c.String()
+	}
+	return strings.Join(conds, " and ")
+}
+
+// Labels returns labels used by the scope in order of first use
+func (s Scope) Labels() []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, c := range s {
+		if !seen[c.Label] {
+			seen[c.Label] = true
+			res = append(res, c.Label)
+		}
+	}
+	return res
+}
+
+// Find returns conditions on label
+func (s Scope) Find(label string) []ScopeCondition {
+	var res []ScopeCondition
+	for _, c := range s {
+		if c.Label == label {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Without returns a copy of the scope without conditions on label
+func (s Scope) Without(label string) Scope {
+	res := make(Scope, 0, len(s))
+	for _, c := range s {
+		if c.Label != label {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Set returns a copy of the scope with conditions on the label of cond replaced by cond.
+// The condition takes the place of the first replaced condition or is appended.
+func (s Scope) Set(cond ScopeCondition) Scope {
+	res := make(Scope, 0, len(s)+1)
+	set := false
+	for _, c := range s {
+		if c.Label != cond.Label {
+			res = append(res, c)
+			continue
+		}
+		if !set {
+			res = append(res, cond)
+			set = true
+		}
+	}
+	if !set {
+		res = append(res, cond)
+	}
+	return res
+}
+
+// ParsedScope parses the scope of the silencing rule
+func (r *SilencingRule) ParsedScope() (Scope, error) {
+	return ParseScope(r.Scope)
+}
+
+// ParsedFilter parses the filter of the alert
+func (a *AlertItem) ParsedFilter() (Scope, error) {
+	return ParseScope(a.Filter)
+}
+
+// ParsedFilter parses the filter of the team
+func (t *TeamItem) ParsedFilter() (Scope, error) {
+	return ParseScope(t.Filter)
+}
+
+func quoteScopeValue(v string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range v {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// ParseScope parses a scope expression, e.g. kubernetes.namespace.name in ("a", "b") and host.hostName = "x".
+// Values may be double or single quoted with backslash escapes, or bare words. An empty expression is an empty scope.
+func ParseScope(expr string) (Scope, error) {
+	tokens, err := lexScope(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &scopeParser{tokens: tokens}
+
+	var s Scope
+
+	for !p.done() {
+		if len(s) > 0 {
+			if err := p.keyword("and"); err != nil {
+				return nil, err
+			}
+		}
+
+		c, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+
+		s = append(s, c)
+	}
+
+	return s, nil
+}
+
+type scopeTokenKind int
+
+const (
+	scopeWord scopeTokenKind = iota
+	scopeString
+	scopeSymbol
+)
+
+type scopeToken struct {
+	kind scopeTokenKind
+	text string
+	pos  int
+}
+
+func (t scopeToken) is(kind scopeTokenKind, text string) bool {
+	if t.kind != kind {
+		return false
+	}
+	if kind == scopeWord {
+		return strings.EqualFold(t.text, text)
+	}
+	return t.text == text
+}
+
+func lexScope(expr string) ([]scopeToken, error) {
+	var tokens []scopeToken
+
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '=':
+			tokens = append(tokens, scopeToken{kind: scopeSymbol, text: string(r), pos: i})
+			i++
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+			}
+			tokens = append(tokens, scopeToken{kind: scopeSymbol, text: SCOPE_OP_NOT_EQ, pos: i})
+			i += 2
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			start := i
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					b.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated %c quote at position %d", r, start)
+			}
+			tokens = append(tokens, scopeToken{kind: scopeString, text: b.String(), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !isScopeDelimiter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, scopeToken{kind: scopeWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+// isScopeDelimiter reports whether the rune ends a bare word of a scope
+func isScopeDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("()=,!\"'", r)
+}
+
+type scopeParser struct {
+	tokens []scopeToken
+	pos    int
+}
+
+func (p *scopeParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *scopeParser) next() (scopeToken, error) {
+	if p.done() {
+		return scopeToken{}, fmt.Errorf("unexpected end of scope")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *scopeParser) peek(kind scopeTokenKind, text string) bool {
+	return !p.done() && p.tokens[p.pos].is(kind, text)
+}
+
+func (p *scopeParser) expect(kind scopeTokenKind, text string) error {
+	t, err := p.next()
+	if err != nil {
+		return fmt.Errorf("expected %q: %w", text, err)
+	}
+	if !t.is(kind, text) {
+		return fmt.Errorf("expected %q at position %d, got %q", text, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *scopeParser) keyword(word string) error {
+	if p.peek(scopeWord, "or") {
+		return fmt.Errorf("unsupported \"or\" at position %d, scopes are conjunctions of conditions", p.tokens[p.pos].pos)
+	}
+	return p.expect(scopeWord, word)
+}
+
+func (p *scopeParser) condition() (ScopeCondition, error) {
+	label, err := p.next()
+	if err != nil {
+		return ScopeCondition{}, err
+	}
+	if label.kind != scopeWord {
+		return ScopeCondition{}, fmt.Errorf("expected label at position %d, got %q", label.pos, label.text)
+	}
+
+	c := ScopeCondition{Label: label.text}
+
+	op, err := p.next()
+	if err != nil {
+		return ScopeCondition{}, fmt.Errorf("expected operator after %q: %w", label.text, err)
+	}
+
+	switch {
+	case op.is(scopeSymbol, SCOPE_OP_EQ):
+		c.Op = SCOPE_OP_EQ
+	case op.is(scopeSymbol, SCOPE_OP_NOT_EQ):
+		c.Op = SCOPE_OP_NOT_EQ
+	case op.is(scopeWord, "in"):
+		c.Op = SCOPE_OP_IN
+	case op.is(scopeWord, "not"):
+		if err := p.expect(scopeWord, "in"); err != nil {
+			return ScopeCondition{}, err
+		}
+		c.Op = SCOPE_OP_NOT_IN
+	case op.is(scopeWord, "contains"):
+		c.Op = SCOPE_OP_CONTAINS
+	case op.is(scopeWord, "starts"):
+		if err := p.expect(scopeWord, "with"); err != nil {
+			return ScopeCondition{}, err
+		}
+		c.Op = SCOPE_OP_STARTS_WITH
+	default:
+		return ScopeCondition{}, fmt.Errorf("unknown operator %q at position %d", op.text, op.pos)
+	}
+
+	if c.Op == SCOPE_OP_IN || c.Op == SCOPE_OP_NOT_IN {
+		c.Values, err = p.list()
+	} else {
+		var v string
+		v, err = p.value()
+		c.Values = []string{v}
+	}
+	if err != nil {
+		return ScopeCondition{}, err
+	}
+
+	return c, nil
+}
+
+func (p *scopeParser) list() ([]string, error) {
+	if err := p.expect(scopeSymbol, "("); err != nil {
+		return nil, err
+	}
+
+	var values []string
+
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		if p.peek(scopeSymbol, ",") {
+			p.pos++
+			continue
+		}
+
+		return values, p.expect(scopeSymbol, ")")
+	}
+}
+
+func (p *scopeParser) value() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", fmt.Errorf("expected value: %w", err)
+	}
+	if t.kind == scopeSymbol {
+		return "", fmt.Errorf("expected value at position %d, got %q", t.pos, t.text)
+	}
+	return t.text, nil
+}
+
+// Matches reports whether the label values satisfy the condition, a missing label has an empty value
+func (c ScopeCondition) Matches(labels map[string]string) bool {
+	return c.matchesValue(labels[c.Label])
+}
+
+// Matches reports whether the label values satisfy all conditions of the scope, a missing label has an empty value
+func (s Scope) Matches(labels map[string]string) bool {
+	for _, c := range s {
+		if !c.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesValue reports whether a label value satisfies the condition
+func (c ScopeCondition) matchesValue(v string) bool {
+	switch c.Op {
+	case SCOPE_OP_EQ, SCOPE_OP_IN:
+		return containsString(c.Values, v)
+	case SCOPE_OP_NOT_EQ, SCOPE_OP_NOT_IN:
+		return !containsString(c.Values, v)
+	case SCOPE_OP_CONTAINS:
+		return len(c.Values) > 0 && strings.Contains(v, c.Values[0])
+	case SCOPE_OP_STARTS_WITH:
+		return len(c.Values) > 0 && strings.HasPrefix(v, c.Values[0])
+	}
+	return false
+}
+
+// finite reports whether the condition matches only the listed values
+func (c ScopeCondition) finite() bool {
+	return c.Op == SCOPE_OP_EQ || c.Op == SCOPE_OP_IN
+}
+
+// implies reports whether every value matching c also matches other, false when it cannot be decided
+func (c ScopeCondition) implies(other ScopeCondition) bool {
+	if c.Label != other.Label {
+		return false
+	}
+
+	if c.finite() {
+		for _, v := range c.Values {
+			if !other.matchesValue(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch other.Op {
+	case SCOPE_OP_NOT_EQ, SCOPE_OP_NOT_IN:
+		// values excluded by other must be excluded by c as well
+		for _, v := range other.Values {
+			if c.matchesValue(v) {
+				return false
+			}
+		}
+		return true
+	case SCOPE_OP_CONTAINS:
+		return (c.Op == SCOPE_OP_CONTAINS || c.Op == SCOPE_OP_STARTS_WITH) && len(c.Values) > 0 &&
+			other.matchesValue(c.Values[0])
+	case SCOPE_OP_STARTS_WITH:
+		return c.Op == SCOPE_OP_STARTS_WITH && len(c.Values) > 0 && other.matchesValue(c.Values[0])
+	}
+
+	return false
+}
+
+// disjoint reports whether no value matches both conditions, false when it cannot be decided
+func (c ScopeCondition) disjoint(other ScopeCondition) bool {
+	if c.Label != other.Label {
+		return false
+	}
+
+	if !c.finite() && other.finite() {
+		c, other = other, c
+	}
+
+	if c.finite() {
+		for _, v := range c.Values {
+			if other.matchesValue(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if c.Op == SCOPE_OP_STARTS_WITH && other.Op == SCOPE_OP_STARTS_WITH && len(c.Values) > 0 && len(other.Values) > 0 {
+		a, b := c.Values[0], other.Values[0]
+		return !strings.HasPrefix(a, b) && !strings.HasPrefix(b, a)
+	}
+
+	return false
+}
+
+// Contains reports whether the scope matches everything other matches.
+// The check is conservative, false is returned when containment cannot be proven.
+func (s Scope) Contains(other Scope) bool {
+	for _, c := range s {
+		implied := false
+		for _, o := range other {
+			if o.implies(c) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps reports whether the scope and other can match the same labels.
+// The check is conservative, true is returned unless the scopes are proven disjoint.
+func (s Scope) Overlaps(other Scope) bool {
+	for _, c := range s {
+		for _, o := range other {
+			if c.disjoint(o) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package sdclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScopeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope Scope
+		want  string
+	}{
+		{"empty", Scope{}, ""},
+		{"eq", And(Eq("host.hostName", "x")), `host.hostName = "x"`},
+		{"not eq", And(NotEq("host.hostName", "x")), `host.hostName != "x"`},
+		{"in", And(In("kubernetes.namespace.name", "a", "b")), `kubernetes.namespace.name in ("a", "b")`},
+		{"in single value", And(In("kubernetes.namespace.name", "a")), `kubernetes.namespace.name in ("a")`},
+		{"not in", And(NotIn("kubernetes.namespace.name", "a", "b")), `kubernetes.namespace.name not in ("a", "b")`},
+		{"contains", And(Contains("container.image", "nginx")), `container.image contains "nginx"`},
+		{"starts with", And(StartsWith("container.image", "nginx:")), `container.image starts with "nginx:"`},
+		{"empty value", And(Eq("host.hostName", "")), `host.hostName = ""`},
+		{
+			"conjunction",
+			And(Eq("host.hostName", "x"), In("kubernetes.namespace.name", "a", "b")),
+			`host.hostName = "x" and kubernetes.namespace.name in ("a", "b")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.scope.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+
+			got := tt.scope.String()
+			if got != tt.want {
+				t.Fatalf("String() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := ParseScope(got)
+			if err != nil {
+				t.Fatalf("ParseScope(%q) = %v", got, err)
+			}
+
+			if len(parsed) != len(tt.scope) || (len(parsed) > 0 && !reflect.DeepEqual(parsed, tt.scope)) {
+				t.Fatalf("ParseScope(%q) = %#v, want %#v", got, parsed, tt.scope)
+			}
+		})
+	}
+}
+
+func TestScopeConditionNormalized(t *testing.T) {
+	tests := []struct {
+		name string
+		cond ScopeCondition
+		want ScopeCondition
+	}{
+		{
+			"eq with several values",
+			ScopeCondition{Label: "a", Op: SCOPE_OP_EQ, Values: []string{"x", "y"}},
+			In("a", "x", "y"),
+		},
+		{
+			"not eq with several values",
+			ScopeCondition{Label: "a", Op: SCOPE_OP_NOT_EQ, Values: []string{"x", "y"}},
+			NotIn("a", "x", "y"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cond.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+
+			parsed, err := ParseScope(tt.cond.String())
+			if err != nil {
+				t.Fatalf("ParseScope(%q) = %v", tt.cond.String(), err)
+			}
+
+			if !reflect.DeepEqual(parsed, Scope{tt.want}) {
+				t.Fatalf("ParseScope(%q) = %#v, want %#v", tt.cond.String(), parsed, Scope{tt.want})
+			}
+		})
+	}
+}
+
+func TestScopeConditionValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cond ScopeCondition
+		want string
+	}{
+		{"empty label", Eq("", "x"), "label is empty"},
+		{"label with space", Eq("host name", "x"), "must not contain"},
+		{"label with quote", Eq(`host"name`, "x"), "must not contain"},
+		{"in without values", In("a"), "has no values"},
+		{"not in without values", NotIn("a"), "has no values"},
+		{"eq without value", ScopeCondition{Label: "a", Op: SCOPE_OP_EQ}, "has no value"},
+		{"contains with several values", ScopeCondition{Label: "a", Op: SCOPE_OP_CONTAINS, Values: []string{"x", "y"}}, "exactly one value"},
+		{"starts with without value", ScopeCondition{Label: "a", Op: SCOPE_OP_STARTS_WITH}, "exactly one value"},
+		{"unknown operator", ScopeCondition{Label: "a", Op: "like", Values: []string{"x"}}, "unknown operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cond.Validate()
+			if err == nil {
+				t.Fatalf("Validate() = nil, want error containing %q", tt.want)
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() = %q, want error containing %q", err, tt.want)
+			}
+
+			if _, err := ParseScope(tt.cond.String()); err == nil {
+				t.Fatalf("ParseScope(%q) accepted an invalid condition", tt.cond.String())
+			}
+		})
+	}
+
+	if err := And(Eq("a", "x"), In("b")).Validate(); err == nil || !strings.HasPrefix(err.Error(), "condition 1:") {
+		t.Fatalf("Scope.Validate() = %v, want error of condition 1", err)
+	}
+}
+
+func TestScopeEscaping(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		rendered string
+	}{
+		{"double quote", `say "hi"`, `a = "say \"hi\""`},
+		{"backslash", `C:\dir`, `a = "C:\\dir"`},
+		{"trailing backslash", `dir\`, `a = "dir\\"`},
+		{"single quote", `it's`, `a = "it's"`},
+		{"operators", `x = y and z in (1, 2)`, `a = "x = y and z in (1, 2)"`},
+		{"unicode", "zażółć", `a = "zażółć"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Eq("a", tt.value).String()
+			if got != tt.rendered {
+				t.Fatalf("String() = %q, want %q", got, tt.rendered)
+			}
+
+			parsed, err := ParseScope(got)
+			if err != nil {
+				t.Fatalf("ParseScope(%q) = %v", got, err)
+			}
+
+			if len(parsed) != 1 || len(parsed[0].Values) != 1 || parsed[0].Values[0] != tt.value {
+				t.Fatalf("ParseScope(%q) = %#v, want value %q", got, parsed, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want Scope
+	}{
+		{"blank", "  ", nil},
+		{"bare word", "a = x", And(Eq("a", "x"))},
+		{"single quoted", `a = 'it\'s'`, And(Eq("a", "it's"))},
+		{"case insensitive keywords", `a IN ("x") AND b Not In ("y") and c STARTS WITH "z"`, And(In("a", "x"), NotIn("b", "y"), StartsWith("c", "z"))},
+		{"no spaces", `a="x" and b!="y"`, And(Eq("a", "x"), NotEq("b", "y"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScope(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseScope(%q) = %v", tt.expr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseScope(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScopeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"unterminated double quote", `a = "x`, "unterminated \" quote at position 4"},
+		{"unterminated single quote", `a = 'x`, "unterminated ' quote at position 4"},
+		{"escaped closing quote", `a = "x\"`, "unterminated \" quote at position 4"},
+		{"lone bang", `a ! "x"`, "unexpected '!' at position 2"},
+		{"unknown operator", `a like "x"`, `unknown operator "like" at position 2`},
+		{"missing operator", `a`, `expected operator after "a"`},
+		{"missing value", `a =`, "expected value"},
+		{"symbol as value", `a = )`, `expected value at position 4, got ")"`},
+		{"empty list", `a in ()`, `expected value at position 6, got ")"`},
+		{"missing list", `a in "x"`, `expected "(" at position 5, got "x"`},
+		{"unclosed list", `a in ("x"`, `expected ")"`},
+		{"not without in", `a not "x"`, `expected "in" at position 6, got "x"`},
+		{"starts without with", `a starts "x"`, `expected "with" at position 9, got "x"`},
+		{"or", `a = "x" or b = "y"`, `unsupported "or" at position 8`},
+		{"missing and", `a = "x" b = "y"`, `expected "and" at position 8, got "b"`},
+		{"quoted label", `"a" = "x"`, `expected label at position 0, got "a"`},
+		{"positions count runes", `ż = "x" or b = "y"`, `unsupported "or" at position 8`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScope(tt.expr)
+			if err == nil {
+				t.Fatalf("ParseScope(%q) = nil error, want %q", tt.expr, tt.want)
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseScope(%q) = %q, want error containing %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
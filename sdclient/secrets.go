@@ -0,0 +1,281 @@
+package sdclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RedactedSecret replaces secret values in printed output and exports
+const RedactedSecret = "[REDACTED]"
+
+// Secret is a sensitive option value such as a webhook URL or an API key.
+// It is redacted when printed, its value is sent to the API as it is.
+// A secret can be a reference like ${env:SLACK_URL} or ${file:/run/secrets/key}
+// that is resolved only when a notification channel is created or updated.
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return RedactedSecret
+}
+
+// GoString redacts the secret in %#v output
+func (s Secret) GoString() string {
+	return fmt.Sprintf("sdclient.Secret(%q)", s.String())
+}
+
+// Value returns the secret in plain text
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// IsReference reports whether the secret is a reference in the ${scheme:name} form
+func (s Secret) IsReference() bool {
+	_, _, ok := parseSecretReference(string(s))
+	return ok
+}
+
+// SecretResolver resolves secret references to their values
+type SecretResolver interface {
+	// ResolveSecret returns the value of a reference in the ${scheme:name} form
+	ResolveSecret(scheme, name string) (string, error)
+}
+
+// SecretResolverFunc is a function implementing SecretResolver
+type SecretResolverFunc func(scheme, name string) (string, error)
+
+// ResolveSecret calls f(scheme, name)
+func (f SecretResolverFunc) ResolveSecret(scheme, name string) (string, error) {
+	return f(scheme, name)
+}
+
+// DefaultSecretResolver resolves ${env:NAME} from environment variables and ${file:PATH} from file contents
+var DefaultSecretResolver SecretResolver = SecretResolverFunc(func(scheme, name string) (string, error) {
+	switch scheme {
+	case "env":
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return v, nil
+	case "file":
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unsupported secret reference scheme %q", scheme)
+	}
+})
+
+// WithSecretResolver sets the resolver of secret references in notification channel options.
+func (c *Client) WithSecretResolver(r SecretResolver) *Client {
+	c.SecretResolver = r
+	return c
+}
+
+// Redacted returns a copy of the notification channel with secret option values redacted
+func (nc NotificationChannelItem) Redacted() (NotificationChannelItem, error) {
+	return nc.mapSecrets(func(s Secret) (Secret, error) {
+		if s == "" || s.IsReference() {
+			return s, nil
+		}
+		return RedactedSecret, nil
+	})
+}
+
+// BackupSecretEnvPrefix prefixes environment variables of secret references written to backups in place of secrets
+const BackupSecretEnvPrefix = "SDCLIENT_CHANNEL_"
+
+// redactedAsReferences returns a copy of the notification channel with secret option values replaced by
+// ${env:SDCLIENT_CHANNEL_<id>_SECRET_<n>} references and the list of references in option order
+func (nc NotificationChannelItem) redactedAsReferences() (NotificationChannelItem, []string, error) {
+	var refs []string
+	res, err := nc.mapSecrets(func(s Secret) (Secret, error) {
+		if s == "" || s.IsReference() {
+			return s, nil
+		}
+		ref := fmt.Sprintf("${env:%s%d_SECRET_%d}", BackupSecretEnvPrefix, nc.ID, len(refs)+1)
+		refs = append(refs, ref)
+		return Secret(ref), nil
+	})
+	return res, refs, err
+}
+
+// hasRedactedSecrets reports whether secrets of the notification channel were redacted
+func (nc NotificationChannelItem) hasRedactedSecrets() bool {
+	redacted := false
+	_, _ = nc.mapSecrets(func(s Secret) (Secret, error) {
+		if s == RedactedSecret {
+			redacted = true
+		}
+		return s, nil
+	})
+	return redacted
+}
+
+// resolveSecrets returns a copy of the notification channel with secret references resolved
+func (c *Client) resolveSecrets(nc NotificationChannelItem) (NotificationChannelItem, error) {
+	resolver := c.SecretResolver
+	if resolver == nil {
+		resolver = DefaultSecretResolver
+	}
+
+	return nc.mapSecrets(func(s Secret) (Secret, error) {
+		scheme, name, ok := parseSecretReference(string(s))
+		if !ok {
+			return s, nil
+		}
+
+		v, err := resolver.ResolveSecret(scheme, name)
+		if err != nil {
+			return "", fmt.Errorf("resolving secret %s: %w", string(s), err)
+		}
+
+		return Secret(v), nil
+	})
+}
+
+// secretOptions is implemented by notification channel options holding secrets
+type secretOptions interface {
+	mapSecrets(fn func(Secret) (Secret, error)) error
+}
+
+// mapSecrets returns a copy of the notification channel with fn applied to every secret option value.
+// Option fields unknown to the options structs are preserved.
+func (nc NotificationChannelItem) mapSecrets(fn func(Secret) (Secret, error)) (NotificationChannelItem, error) {
+	if nc.Options == nil {
+		return nc, nil
+	}
+
+	opts, err := nc.DecodeOptions()
+	if err != nil {
+		return nc, err
+	}
+
+	so, ok := opts.(secretOptions)
+	if !ok {
+		return nc, nil
+	}
+
+	changed := false
+	err = so.mapSecrets(func(s Secret) (Secret, error) {
+		v, err := fn(s)
+		if v != s {
+			changed = true
+		}
+		return v, err
+	})
+	if err != nil {
+		return nc, err
+	}
+
+	if !changed {
+		return nc, nil
+	}
+
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nc, err
+	}
+
+	merged := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(*nc.Options, &merged); err != nil {
+		return nc, err
+	}
+
+	updated := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &updated); err != nil {
+		return nc, err
+	}
+
+	for k, v := range updated {
+		merged[k] = v
+	}
+
+	if body, err = json.Marshal(merged); err != nil {
+		return nc, err
+	}
+
+	raw := json.RawMessage(body)
+	nc.Options = &raw
+
+	return nc, nil
+}
+
+func (nc *PagerDutyNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretFields(fn, &nc.ServiceKey)
+}
+
+func (nc *SlackNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretFields(fn, &nc.URL)
+}
+
+func (nc *WebhookNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretHeaders(fn, nc.AdditionalHeaders)
+}
+
+func (nc *OpsGenieNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretFields(fn, &nc.APIKey)
+}
+
+func (nc *VictorOpsNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretFields(fn, &nc.APIKey)
+}
+
+func (nc *MSTeamsNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretFields(fn, &nc.URL)
+}
+
+func (nc *PrometheusAlertManagerNotificationChannelOptions) mapSecrets(fn func(Secret) (Secret, error)) error {
+	return mapSecretHeaders(fn, nc.AdditionalHeaders)
+}
+
+func mapSecretFields(fn func(Secret) (Secret, error), fields ...*Secret) error {
+	for _, f := range fields {
+		v, err := fn(*f)
+		if err != nil {
+			return err
+		}
+		*f = v
+	}
+	return nil
+}
+
+// mapSecretHeaders applies fn to the headers in key order
+func mapSecretHeaders(fn func(Secret) (Secret, error), headers map[string]Secret) error {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, err := fn(headers[k])
+		if err != nil {
+			return err
+		}
+		headers[k] = v
+	}
+	return nil
+}
+
+// parseSecretReference splits a ${scheme:name} reference
+func parseSecretReference(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "${") || !strings.HasSuffix(s, "}") {
+		return "", "", false
+	}
+
+	scheme, name, ok := strings.Cut(s[2:len(s)-1], ":")
+	if !ok || scheme == "" || name == "" {
+		return "", "", false
+	}
+
+	return scheme, name, true
+}
@@ -4,18 +4,57 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 )
 
+// BulkDeleteChunkSize is the largest number of IDs sent in a single bulk delete request
+const BulkDeleteChunkSize = 100
+
+// BulkDeleteRules represents a bulk delete of silencing rules request object
 type BulkDeleteRules struct {
 	SilencingRules RuleIdList `json:"silencingRules"`
 }
 
+// RuleIdList represents a list of silencing rule IDs
 type RuleIdList struct {
 	Ids []int `json:"ids"`
 }
 
+// BulkResult reports the outcome of a bulk delete for every ID
+type BulkResult struct {
+	Deleted []int
+	Missing []int
+	Failed  map[int]error
+}
+
+// Err returns an error describing failed IDs, nil when no ID failed
+func (r *BulkResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(r.Failed))
+	for id := range r.Failed {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("%d: %v", id, r.Failed[id]))
+	}
+
+	return fmt.Errorf("failed to delete %d silencing rules: %s", len(ids), strings.Join(msgs, "; "))
+}
+
+// SilencingRule represents a single silencing rule.
+// StartTs is in milliseconds since the Unix epoch and DurationInSec in seconds,
+// Start, End and Duration return them as time values.
 type SilencingRule struct {
 	ID                     int    `json:"id,omitempty"`
 	Version                int    `json:"version,omitempty"`
@@ -31,6 +70,83 @@ type SilencingRule struct {
 	NotificationChannelIds []int  `json:"notificationChannelIds,omitempty"`
 }
 
+// NewSilencingRule creates a new enabled silencing rule silencing scope from start for dur.
+// The duration is rounded up to whole seconds.
+func NewSilencingRule(name, scope string, start time.Time, dur time.Duration) (*SilencingRule, error) {
+	rule := &SilencingRule{
+		Name:    name,
+		Scope:   scope,
+		Enabled: true,
+	}
+	rule.SetWindow(start, dur)
+
+	if err := rule.ValidateAt(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// SetWindow sets the start and the duration of the silence, the duration is rounded up to whole seconds
+func (r *SilencingRule) SetWindow(start time.Time, dur time.Duration) {
+	r.StartTs = start.UnixNano() / int64(time.Millisecond)
+	r.DurationInSec = int64((dur + time.Second - 1) / time.Second)
+}
+
+// Start returns the start of the silence
+func (r *SilencingRule) Start() time.Time {
+	return time.Unix(0, r.StartTs*int64(time.Millisecond))
+}
+
+// Duration returns the length of the silence
+func (r *SilencingRule) Duration() time.Duration {
+	return time.Duration(r.DurationInSec) * time.Second
+}
+
+// End returns the end of the silence
+func (r *SilencingRule) End() time.Time {
+	return r.Start().Add(r.Duration())
+}
+
+// IsActive reports whether the rule is enabled and silences at now
+func (r *SilencingRule) IsActive(now time.Time) bool {
+	return r.Enabled && !now.Before(r.Start()) && now.Before(r.End())
+}
+
+// Remaining returns the time left from now until the end of the silence, zero once it ended
+func (r *SilencingRule) Remaining(now time.Time) time.Duration {
+	if d := r.End().Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// ValidateAt checks the rule before it is sent to the API, the silence must not end before now.
+// The returned error is ValidationErrors listing every problem found.
+func (r *SilencingRule) ValidateAt(now time.Time) error {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(r.Name) == "" {
+		errs.add("name", "is required")
+	}
+
+	if r.DurationInSec <= 0 {
+		errs.add("durationInSec", "must be positive, got %d", r.DurationInSec)
+	} else if !r.End().After(now) {
+		errs.add("startTs", "silence ends in the past at %s", r.End().Format(time.RFC3339))
+	}
+
+	if r.StartTs <= 0 {
+		errs.add("startTs", "is required")
+	}
+
+	if msg := checkExpressionSyntax(r.Scope); msg != "" {
+		errs.add("scope", "%s", msg)
+	}
+
+	return errs.err()
+}
+
 // ListSilencingRules returns a list of silencing rules.
 func (c *Client) ListSilencingRules() ([]SilencingRule, error) {
 	return c.ListSilencingRulesWithContext(context.Background())
@@ -79,6 +195,111 @@ func (c *Client) GetSilencingRuleWithContext(ctx context.Context, id int) (*Sile
 	return res, nil
 }
 
+// CreateSilencingRule creates a silencing rule.
+func (c *Client) CreateSilencingRule(rule *SilencingRule) (*SilencingRule, error) {
+	return c.CreateSilencingRuleWithContext(context.Background(), rule)
+}
+
+// CreateSilencingRuleWithContext creates a silencing rule.
+func (c *Client) CreateSilencingRuleWithContext(ctx context.Context, rule *SilencingRule) (*SilencingRule, error) {
+
+	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_SILENCERULES)
+
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res = new(SilencingRule)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateSilencingRule updates a silencing rule.
+func (c *Client) UpdateSilencingRule(rule *SilencingRule) (*SilencingRule, error) {
+	return c.UpdateSilencingRuleWithContext(context.Background(), rule)
+}
+
+// UpdateSilencingRuleWithContext updates a silencing rule, see IsConflict for version handling
+func (c *Client) UpdateSilencingRuleWithContext(ctx context.Context, rule *SilencingRule) (*SilencingRule, error) {
+
+	if rule.Version == 0 {
+		current, err := c.GetSilencingRuleWithContext(ctx, rule.ID)
+		if err != nil {
+			return nil, err
+		}
+		rule.Version = current.Version
+	}
+
+	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_SILENCERULES, rule.ID)
+
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res = new(SilencingRule)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// EnableSilencingRule enables a silencing rule.
+func (c *Client) EnableSilencingRule(id int) (*SilencingRule, error) {
+	return c.EnableSilencingRuleWithContext(context.Background(), id)
+}
+
+// EnableSilencingRuleWithContext enables a silencing rule keeping all other settings.
+func (c *Client) EnableSilencingRuleWithContext(ctx context.Context, id int) (*SilencingRule, error) {
+	return c.setSilencingRuleEnabled(ctx, id, true)
+}
+
+// DisableSilencingRule disables a silencing rule.
+func (c *Client) DisableSilencingRule(id int) (*SilencingRule, error) {
+	return c.DisableSilencingRuleWithContext(context.Background(), id)
+}
+
+// DisableSilencingRuleWithContext disables a silencing rule keeping all other settings.
+func (c *Client) DisableSilencingRuleWithContext(ctx context.Context, id int) (*SilencingRule, error) {
+	return c.setSilencingRuleEnabled(ctx, id, false)
+}
+
+func (c *Client) setSilencingRuleEnabled(ctx context.Context, id int, enabled bool) (*SilencingRule, error) {
+	rule, err := c.GetSilencingRuleWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.Enabled == enabled {
+		return rule, nil
+	}
+
+	rule.Enabled = enabled
+
+	return c.UpdateSilencingRuleWithContext(ctx, rule)
+}
+
 // DeleteSilencingRule deletes a silencing rule.
 func (c *Client) DeleteSilencingRule(id int) error {
 	return c.DeleteSilencingRuleWithContext(context.Background(), id)
@@ -102,67 +323,108 @@ func (c *Client) DeleteSilencingRuleWithContext(ctx context.Context, id int) err
 }
 
 // DeleteSilencingRules deletes a list of silencing rules.
-func (c *Client) DeleteSilencingRules(ruleIds []int) error {
+func (c *Client) DeleteSilencingRules(ruleIds []int) (*BulkResult, error) {
 	return c.DeleteSilencingRulesWithContext(context.Background(), ruleIds)
 }
 
 // DeleteSilencingRulesWithContext deletes a list of silencing rules.
-func (c *Client) DeleteSilencingRulesWithContext(ctx context.Context, ruleIds []int) error {
-	fullURL := fmt.Sprintf("%s%s/delete", c.Endpoint, URI_SILENCERULES)
+// IDs missing from ListSilencingRules are reported as missing without being sent. The remaining IDs
+// are deleted in chunks of BulkDeleteChunkSize, a chunk rejected by the bulk endpoint with a client error
+// is deleted rule by rule, other errors fail the whole chunk. The result reports the outcome for every ID,
+// the returned error is set when any rule could not be deleted.
+func (c *Client) DeleteSilencingRulesWithContext(ctx context.Context, ruleIds []int) (*BulkResult, error) {
 
-	dr := &BulkDeleteRules{
-		SilencingRules: RuleIdList{
-			Ids: ruleIds,
-		},
-	}
+	res := &BulkResult{Failed: make(map[int]error)}
 
-	body, err := json.Marshal(dr)
+	rules, err := c.ListSilencingRulesWithContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(body))
-	if err != nil {
-		return err
+	existing := make(map[int]bool, len(rules))
+	for _, r := range rules {
+		existing[r.ID] = true
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	ids := make([]int, 0, len(ruleIds))
+	for _, id := range ruleIds {
+		switch {
+		case containsID(ids, id) || containsID(res.Missing, id):
+		case !existing[id]:
+			res.Missing = append(res.Missing, id)
+		default:
+			ids = append(ids, id)
+		}
+	}
 
-	if err := c.sendRequest(req, nil); err != nil {
-		return nil
+	for start := 0; start < len(ids); start += BulkDeleteChunkSize {
+		end := start + BulkDeleteChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+
+		err := c.bulkDeleteSilencingRules(ctx, chunk)
+		if err == nil {
+			res.Deleted = append(res.Deleted, chunk...)
+			continue
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode >= http.StatusInternalServerError {
+			for _, id := range chunk {
+				res.Failed[id] = err
+			}
+			if ctx.Err() != nil {
+				for _, id := range ids[end:] {
+					res.Failed[id] = ctx.Err()
+				}
+				break
+			}
+			continue
+		}
+
+		for _, id := range chunk {
+			err := c.DeleteSilencingRuleWithContext(ctx, id)
+			switch {
+			case err == nil:
+				res.Deleted = append(res.Deleted, id)
+			case IsNotFound(err):
+				res.Missing = append(res.Missing, id)
+			default:
+				res.Failed[id] = err
+			}
+		}
 	}
 
-	return nil
+	return res, res.Err()
 }
 
-// CreateSilencingRule creates a silencing rule.
-func (c *Client) CreateSilencingRule(rule *SilencingRule) (*SilencingRule, error) {
-	return c.CreateSilencingRuleWithContext(context.Background(), rule)
-}
+func (c *Client) bulkDeleteSilencingRules(ctx context.Context, ruleIds []int) error {
 
-// CreateSilencingRuleWithContext creates a silencing rule.
-func (c *Client) CreateSilencingRuleWithContext(ctx context.Context, rule *SilencingRule) (*SilencingRule, error) {
-	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_SILENCERULES)
+	fullURL := fmt.Sprintf("%s%s/delete", c.Endpoint, URI_SILENCERULES)
 
-	body, err := json.Marshal(rule)
+	dr := &BulkDeleteRules{
+		SilencingRules: RuleIdList{
+			Ids: ruleIds,
+		},
+	}
+
+	body, err := json.Marshal(dr)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	var res = new(SilencingRule)
-
-	if err := c.sendRequest(req, res); err != nil {
-		return nil, err
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
 	}
 
-	return res, nil
+	return nil
 }
@@ -0,0 +1,167 @@
+package sdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultSilenceNamePrefix prefixes names of silencing rules created by SilenceFor when SilenceOptions.NamePrefix is not set
+const DefaultSilenceNamePrefix = "silence"
+
+// SilenceOptions configures SilenceFor
+type SilenceOptions struct {
+	// Reason is added to the generated rule name
+	Reason string
+	// NamePrefix prefixes the generated rule name, DefaultSilenceNamePrefix if empty
+	NamePrefix string
+	// NotificationChannelIds restricts the silence to the notification channels, all channels are silenced if empty
+	NotificationChannelIds []int
+	TeamID                 int
+}
+
+// errSilenceDeleted is returned by handles of silences deleted by End
+var errSilenceDeleted = errors.New("silence was deleted")
+
+// Silence is a handle of a silencing rule created by SilenceFor
+type Silence struct {
+	// Rule is the rule as last written by the handle, nil after End deleted a silence that did not start yet
+	Rule *SilencingRule
+
+	client *Client
+}
+
+// SilenceFor silences scope starting now for d
+func (c *Client) SilenceFor(scope string, d time.Duration, opts SilenceOptions) (*Silence, error) {
+	return c.SilenceForWithContext(context.Background(), scope, d, opts)
+}
+
+// SilenceForWithContext silences scope starting now for d.
+// The rule name is generated from the prefix, the start time and the reason.
+func (c *Client) SilenceForWithContext(ctx context.Context, scope string, d time.Duration, opts SilenceOptions) (*Silence, error) {
+
+	prefix := opts.NamePrefix
+	if prefix == "" {
+		prefix = DefaultSilenceNamePrefix
+	}
+
+	reason := opts.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("silenced for %s", d)
+	}
+
+	start := time.Now()
+	name := fmt.Sprintf("%s %s: %s", prefix, start.UTC().Format(time.RFC3339), reason)
+
+	rule, err := NewSilencingRule(name, scope, start, d)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.NotificationChannelIds = opts.NotificationChannelIds
+	rule.TeamID = opts.TeamID
+
+	res, err := c.CreateSilencingRuleWithContext(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Silence{Rule: res, client: c}, nil
+}
+
+// Extend lengthens the silence by d
+func (s *Silence) Extend(d time.Duration) error {
+	return s.ExtendWithContext(context.Background(), d)
+}
+
+// ExtendWithContext lengthens the silence by d, a silence that already ended is restarted now for d
+func (s *Silence) ExtendWithContext(ctx context.Context, d time.Duration) error {
+	if s.Rule == nil {
+		return errSilenceDeleted
+	}
+
+	if d <= 0 {
+		return fmt.Errorf("extension must be positive, got %s", d)
+	}
+
+	rule, err := s.client.GetSilencingRuleWithContext(ctx, s.Rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if now := time.Now(); rule.End().After(now) {
+		rule.SetWindow(rule.Start(), rule.Duration()+d)
+	} else {
+		rule.SetWindow(now, d)
+	}
+
+	res, err := s.client.UpdateSilencingRuleWithContext(ctx, rule)
+	if err != nil {
+		return err
+	}
+
+	s.Rule = res
+
+	return nil
+}
+
+// End ends the silence now
+func (s *Silence) End() error {
+	return s.EndWithContext(context.Background())
+}
+
+// EndWithContext ends the silence now and updates Rule, see EndSilence
+func (s *Silence) EndWithContext(ctx context.Context) error {
+	if s.Rule == nil {
+		return errSilenceDeleted
+	}
+
+	rule, err := s.client.endSilence(ctx, s.Rule.ID)
+	if err != nil {
+		return err
+	}
+
+	s.Rule = rule
+
+	return nil
+}
+
+// EndSilence ends a silence now
+func (c *Client) EndSilence(id int) error {
+	return c.EndSilenceWithContext(context.Background(), id)
+}
+
+// EndSilenceWithContext ends a silence now. A silence that already started is shortened to end now
+// so it stays in the history, a silence that did not start yet is deleted.
+func (c *Client) EndSilenceWithContext(ctx context.Context, id int) error {
+	_, err := c.endSilence(ctx, id)
+	return err
+}
+
+// endSilence ends a silence now and returns its rule, nil when the rule was deleted
+func (c *Client) endSilence(ctx context.Context, id int) (*SilencingRule, error) {
+
+	rule, err := c.GetSilencingRuleWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	switch {
+	case now.Before(rule.Start()):
+		return nil, c.DeleteSilencingRuleWithContext(ctx, id)
+	case !now.Before(rule.End()):
+		return rule, nil
+	}
+
+	elapsed := now.Sub(rule.Start())
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
+	rule.SetWindow(rule.Start(), elapsed)
+
+	return c.UpdateSilencingRuleWithContext(ctx, rule)
+}
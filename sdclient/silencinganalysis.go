@@ -0,0 +1,191 @@
+package sdclient
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// SilencingRuleOverlap describes two enabled silencing rules of a team silencing the same alerts at the same time
+type SilencingRuleOverlap struct {
+	Rule  SilencingRule
+	Other SilencingRule
+	// Contains is true when Rule silences everything Other silences, making Other redundant
+	Contains bool
+}
+
+// SilencingRuleMissingChannels describes a silencing rule referencing notification channels that do not exist
+type SilencingRuleMissingChannels struct {
+	Rule       SilencingRule
+	ChannelIDs []int
+}
+
+// SilencingRuleReport is the outcome of AnalyzeSilencingRules
+type SilencingRuleReport struct {
+	Overlaps []SilencingRuleOverlap
+	// Expired lists enabled rules whose window already ended
+	Expired         []SilencingRule
+	MissingChannels []SilencingRuleMissingChannels
+	// Unparsed lists rules whose scope could not be parsed, they are compared by the scope string only
+	Unparsed []SilencingRule
+}
+
+// Redundant returns rules fully contained by another rule
+func (r *SilencingRuleReport) Redundant() []SilencingRule {
+	var res []SilencingRule
+	seen := make(map[int]bool)
+	for _, o := range r.Overlaps {
+		if o.Contains && !seen[o.Other.ID] {
+			seen[o.Other.ID] = true
+			res = append(res, o.Other)
+		}
+	}
+	return res
+}
+
+// Empty reports whether no issue was found
+func (r *SilencingRuleReport) Empty() bool {
+	return len(r.Overlaps) == 0 && len(r.Expired) == 0 && len(r.MissingChannels) == 0 && len(r.Unparsed) == 0
+}
+
+// AnalyzeSilencingRules fetches silencing rules and notification channels and analyzes the rules, see AnalyzeSilencingRuleList
+func (c *Client) AnalyzeSilencingRules() (*SilencingRuleReport, error) {
+	return c.AnalyzeSilencingRulesWithContext(context.Background())
+}
+
+// AnalyzeSilencingRulesWithContext fetches silencing rules and notification channels and analyzes the rules, see AnalyzeSilencingRuleList
+func (c *Client) AnalyzeSilencingRulesWithContext(ctx context.Context) (*SilencingRuleReport, error) {
+
+	rules, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := c.channelIndex(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return AnalyzeSilencingRuleList(rules, index, time.Now()), nil
+}
+
+// AnalyzeSilencingRuleList finds enabled rules of the same team whose time windows, scopes and notification channels
+// overlap or contain each other, enabled rules that already ended and rules referencing notification channels
+// missing from the index. Channels are not checked when index is nil.
+// Overlaps are reported once per pair, a containing rule is reported as Rule, identical rules keep the lower ID.
+func AnalyzeSilencingRuleList(rules []SilencingRule, index *ChannelIndex, now time.Time) *SilencingRuleReport {
+	report := &SilencingRuleReport{}
+
+	sorted := make([]SilencingRule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	var live []SilencingRule
+	scopes := make(map[int]Scope)
+
+	for _, r := range sorted {
+		if index != nil {
+			var missing []int
+			for _, id := range r.NotificationChannelIds {
+				if _, ok := index.ByID(id); !ok {
+					missing = append(missing, id)
+				}
+			}
+			if len(missing) > 0 {
+				report.MissingChannels = append(report.MissingChannels, SilencingRuleMissingChannels{Rule: r, ChannelIDs: missing})
+			}
+		}
+
+		if !r.Enabled {
+			continue
+		}
+
+		if !r.End().After(now) {
+			report.Expired = append(report.Expired, r)
+			continue
+		}
+
+		scope, err := r.ParsedScope()
+		if err != nil {
+			report.Unparsed = append(report.Unparsed, r)
+		}
+
+		scopes[r.ID] = scope
+		live = append(live, r)
+	}
+
+	for i := range live {
+		for j := i + 1; j < len(live); j++ {
+			a, b := live[i], live[j]
+
+			if a.TeamID != b.TeamID || !windowsOverlap(&a, &b) || !channelsOverlap(a.NotificationChannelIds, b.NotificationChannelIds) {
+				continue
+			}
+
+			sa, sb := ruleScope(a, scopes), ruleScope(b, scopes)
+
+			switch {
+			case ruleContains(&a, &b, sa, sb):
+				report.Overlaps = append(report.Overlaps, SilencingRuleOverlap{Rule: a, Other: b, Contains: true})
+			case ruleContains(&b, &a, sb, sa):
+				report.Overlaps = append(report.Overlaps, SilencingRuleOverlap{Rule: b, Other: a, Contains: true})
+			case scopesOverlap(a, b, sa, sb):
+				report.Overlaps = append(report.Overlaps, SilencingRuleOverlap{Rule: a, Other: b})
+			}
+		}
+	}
+
+	return report
+}
+
+// ruleScope returns the parsed scope of a rule, nil when the scope could not be parsed
+func ruleScope(r SilencingRule, scopes map[int]Scope) *Scope {
+	s := scopes[r.ID]
+	if s == nil && r.Scope != "" {
+		return nil
+	}
+	return &s
+}
+
+// ruleContains reports whether rule a silences everything rule b silences
+func ruleContains(a, b *SilencingRule, sa, sb *Scope) bool {
+	if a.Start().After(b.Start()) || a.End().Before(b.End()) {
+		return false
+	}
+
+	if len(a.NotificationChannelIds) > 0 {
+		if len(b.NotificationChannelIds) == 0 || len(idsDifference(b.NotificationChannelIds, a.NotificationChannelIds)) > 0 {
+			return false
+		}
+	}
+
+	if sa == nil || sb == nil {
+		return a.Scope == b.Scope
+	}
+
+	return sa.Contains(*sb)
+}
+
+func scopesOverlap(a, b SilencingRule, sa, sb *Scope) bool {
+	if sa == nil || sb == nil {
+		return a.Scope == b.Scope
+	}
+	return sa.Overlaps(*sb)
+}
+
+func windowsOverlap(a, b *SilencingRule) bool {
+	return a.Start().Before(b.End()) && b.Start().Before(a.End())
+}
+
+// channelsOverlap reports whether rules with the notification channels silence a common channel, no channels means all channels
+func channelsOverlap(a, b []int) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, id := range a {
+		if containsID(b, id) {
+			return true
+		}
+	}
+	return false
+}
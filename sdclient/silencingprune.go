@@ -0,0 +1,76 @@
+package sdclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PruneOptions configures PruneSilencingRules
+type PruneOptions struct {
+	// NamePrefix limits pruning to rules whose name starts with the prefix
+	NamePrefix string
+	// TeamID limits pruning to rules of the team
+	TeamID int
+	// DryRun selects the rules without deleting them
+	DryRun bool
+}
+
+// PruneResult describes the outcome of PruneSilencingRules
+type PruneResult struct {
+	Cutoff time.Time
+	DryRun bool
+	// Rules lists rules selected for deletion
+	Rules []SilencingRule
+	// Result reports per rule results of the deletion, nil on a dry run or when no rule was selected
+	Result *BulkResult
+}
+
+// PruneSilencingRules deletes silencing rules that ended more than olderThan ago
+func (c *Client) PruneSilencingRules(olderThan time.Duration, opts PruneOptions) (*PruneResult, error) {
+	return c.PruneSilencingRulesWithContext(context.Background(), olderThan, opts)
+}
+
+// PruneSilencingRulesWithContext deletes silencing rules that ended more than olderThan ago.
+// Rules are deleted with the bulk delete endpoint, rules that could not be deleted are reported in the result.
+func (c *Client) PruneSilencingRulesWithContext(ctx context.Context, olderThan time.Duration, opts PruneOptions) (*PruneResult, error) {
+
+	if olderThan < 0 {
+		return nil, fmt.Errorf("olderThan must not be negative, got %s", olderThan)
+	}
+
+	rules, err := c.ListSilencingRulesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PruneResult{Cutoff: time.Now().Add(-olderThan), DryRun: opts.DryRun}
+
+	var ids []int
+
+	for _, r := range rules {
+		if opts.NamePrefix != "" && !strings.HasPrefix(r.Name, opts.NamePrefix) {
+			continue
+		}
+
+		if opts.TeamID != 0 && r.TeamID != opts.TeamID {
+			continue
+		}
+
+		if r.End().After(res.Cutoff) {
+			continue
+		}
+
+		res.Rules = append(res.Rules, r)
+		ids = append(ids, r.ID)
+	}
+
+	if opts.DryRun || len(ids) == 0 {
+		return res, nil
+	}
+
+	res.Result, err = c.DeleteSilencingRulesWithContext(ctx, ids)
+
+	return res, err
+}
@@ -43,6 +43,7 @@ type TeamItem struct {
 	Default             bool                    `json:"default,omitempty"`
 	UsersRole           *UserRoleObject         `json:"usersRole,omitempty"`
 	Users               []string                `json:"users"`
+	UserRoles           []UserRoleObject        `json:"userRoles"`
 }
 
 type NamespaceFiltersObject struct {
@@ -173,3 +174,166 @@ func NewTeam(name, description string) *TeamItem {
 		Users:               []string{},
 	}
 }
+
+// teamMemberRetries limits retries of a membership change rejected because of a version conflict
+const teamMemberRetries = 3
+
+// UpdateTeam updates a team
+func (c *Client) UpdateTeam(team *TeamItem) (*Team, error) {
+	return c.UpdateTeamWithContext(context.Background(), team)
+}
+
+// UpdateTeamWithContext updates a team, see IsConflict for version handling
+func (c *Client) UpdateTeamWithContext(ctx context.Context, team *TeamItem) (*Team, error) {
+
+	if team.Version == 0 {
+		current, err := c.GetTeamWithContext(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		team.Version = current.Team.Version
+	}
+
+	fullURL := fmt.Sprintf("%s%s/%d", c.Endpoint, URI_TEAMS, team.ID)
+
+	body, err := json.Marshal(team)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var res = new(Team)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddTeamMember adds a user to a team with a role, see FindUser for how users are identified
+func (c *Client) AddTeamMember(teamID int, user, role string) (*Team, error) {
+	return c.AddTeamMemberWithContext(context.Background(), teamID, user, role)
+}
+
+// AddTeamMemberWithContext adds a user to a team with a role, the role of an existing member is changed
+func (c *Client) AddTeamMemberWithContext(ctx context.Context, teamID int, user, role string) (*Team, error) {
+	return c.editTeamMember(ctx, teamID, user, role, func(roles []UserRoleObject, i int, userID int) ([]UserRoleObject, error) {
+		if i < 0 {
+			return append(roles, UserRoleObject{UserId: userID, TeamId: teamID, Role: role}), nil
+		}
+		roles[i].Role = role
+		return roles, nil
+	})
+}
+
+// RemoveTeamMember removes a user from a team
+func (c *Client) RemoveTeamMember(teamID int, user string) (*Team, error) {
+	return c.RemoveTeamMemberWithContext(context.Background(), teamID, user)
+}
+
+// RemoveTeamMemberWithContext removes a user from a team, removing a user who is not a member is not an error
+func (c *Client) RemoveTeamMemberWithContext(ctx context.Context, teamID int, user string) (*Team, error) {
+	return c.editTeamMember(ctx, teamID, user, "", func(roles []UserRoleObject, i int, userID int) ([]UserRoleObject, error) {
+		if i < 0 {
+			return roles, nil
+		}
+		return append(roles[:i:i], roles[i+1:]...), nil
+	})
+}
+
+// SetTeamMemberRole changes the role of a team member
+func (c *Client) SetTeamMemberRole(teamID int, user, role string) (*Team, error) {
+	return c.SetTeamMemberRoleWithContext(context.Background(), teamID, user, role)
+}
+
+// SetTeamMemberRoleWithContext changes the role of a team member, the user must already be a member
+func (c *Client) SetTeamMemberRoleWithContext(ctx context.Context, teamID int, user, role string) (*Team, error) {
+	return c.editTeamMember(ctx, teamID, user, role, func(roles []UserRoleObject, i int, userID int) ([]UserRoleObject, error) {
+		if i < 0 {
+			return nil, fmt.Errorf("user %q is not a member of team %d", user, teamID)
+		}
+		roles[i].Role = role
+		return roles, nil
+	})
+}
+
+// editTeamMember applies edit to the user roles of a team, i is the index of the user or -1 when the user is not a member.
+// The team is not updated when the roles are unchanged, updates rejected because of a version conflict are retried.
+func (c *Client) editTeamMember(ctx context.Context, teamID int, user, role string, edit func(roles []UserRoleObject, i int, userID int) ([]UserRoleObject, error)) (*Team, error) {
+
+	if role != "" && !validTeamRole(role) {
+		return nil, fmt.Errorf("invalid team role %q", role)
+	}
+
+	u, err := c.FindUserWithContext(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		team, err := c.GetTeamWithContext(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+
+		before := make([]UserRoleObject, len(team.Team.UserRoles))
+		copy(before, team.Team.UserRoles)
+
+		i := -1
+		for j, r := range before {
+			if r.UserId == u.ID {
+				i = j
+				break
+			}
+		}
+
+		roles, err := edit(team.Team.UserRoles, i, u.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if userRolesEqual(before, roles) {
+			return team, nil
+		}
+
+		// an empty list removes the last member, nil would be sent as null
+		if roles == nil {
+			roles = []UserRoleObject{}
+		}
+		team.Team.UserRoles = roles
+
+		res, err := c.UpdateTeamWithContext(ctx, &team.Team)
+		if IsConflict(err) && attempt < teamMemberRetries {
+			continue
+		}
+
+		return res, err
+	}
+}
+
+func validTeamRole(role string) bool {
+	switch role {
+	case ROLE_TEAM_READ, ROLE_TEAM_STANDARD, ROLE_TEAM_EDIT, ROLE_TEAM_MANAGER:
+		return true
+	}
+	return false
+}
+
+func userRolesEqual(a, b []UserRoleObject) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].UserId != b[i].UserId || a[i].Role != b[i].Role {
+			return false
+		}
+	}
+	return true
+}
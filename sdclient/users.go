@@ -0,0 +1,78 @@
+package sdclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Users struct {
+	Users []UserItem `json:"users,omitempty"`
+}
+
+type User struct {
+	User UserItem `json:"user,omitempty"`
+}
+
+type UserItem struct {
+	ID        int    `json:"id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	Enabled   bool   `json:"enabled,omitempty"`
+}
+
+func (c *Client) ListUsers() (*Users, error) {
+	return c.ListUsersWithContext(context.Background())
+}
+
+func (c *Client) ListUsersWithContext(ctx context.Context) (*Users, error) {
+
+	fullURL := fmt.Sprintf("%s%s", c.Endpoint, URI_USERS)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = new(Users)
+
+	if err := c.sendRequest(req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindUser returns the user with the given ID or email, a numeric value is a user ID
+func (c *Client) FindUser(user string) (*UserItem, error) {
+	return c.FindUserWithContext(context.Background(), user)
+}
+
+// FindUserWithContext returns the user with the given ID or email, a numeric value is a user ID.
+// Emails are matched case insensitively against usernames.
+func (c *Client) FindUserWithContext(ctx context.Context, user string) (*UserItem, error) {
+
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+
+	users, err := c.ListUsersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(user)
+	isID := err == nil
+
+	for i, u := range users.Users {
+		if (isID && u.ID == id) || (!isID && strings.EqualFold(u.Username, user)) {
+			return &users.Users[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %q not found", user)
+}
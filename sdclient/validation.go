@@ -0,0 +1,356 @@
+package sdclient
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// ValidationError describes a single invalid field
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors is a list of all problems found during validation
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ve := range e {
+		msgs = append(msgs, ve.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns nil when there are no validation errors
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the alert before it is sent to the API.
+// The returned error is ValidationErrors listing every problem found.
+func (a *AlertItem) Validate() error {
+	return a.validate("").err()
+}
+
+// ValidateAll validates every alert in the list
+func (a *Alerts) ValidateAll() error {
+	var errs ValidationErrors
+
+	for i, alert := range a.Alerts {
+		prefix := fmt.Sprintf("alerts[%d].", i)
+
+		if alert == nil {
+			errs.add(strings.TrimSuffix(prefix, "."), "alert is nil")
+			continue
+		}
+
+		errs = append(errs, alert.validate(prefix)...)
+	}
+
+	return errs.err()
+}
+
+func (a *AlertItem) validate(prefix string) ValidationErrors {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(a.Name) == "" {
+		errs.add(prefix+"name", "is required")
+	}
+
+	switch a.Type {
+	case "":
+		errs.add(prefix+"type", "is required")
+	case ALERT_TYPE_MANUAL:
+		if strings.TrimSpace(a.Condition) == "" {
+			errs.add(prefix+"condition", "is required for %s alerts", a.Type)
+		} else if !strings.ContainsAny(a.Condition, "<>=") {
+			errs.add(prefix+"condition", "has no comparison operator")
+		}
+		if a.Timespan == 0 {
+			errs.add(prefix+"timespan", "is required for %s alerts", a.Type)
+		}
+	case ALERT_TYPE_PROMETHEUS:
+		if strings.TrimSpace(a.Condition) == "" {
+			errs.add(prefix+"condition", "PromQL query is required for %s alerts", a.Type)
+		}
+	}
+
+	if ts := int64(a.Timespan); ts != 0 && (ts < ALERT_TIMESPAN_MIN || ts > ALERT_TIMESPAN_MAX) {
+		errs.add(prefix+"timespan", "must be between %d and %d microseconds, got %d", ALERT_TIMESPAN_MIN, ALERT_TIMESPAN_MAX, a.Timespan)
+	}
+
+	if a.Severity < ALERT_SEVERITY_MIN || a.Severity > ALERT_SEVERITY_MAX {
+		errs.add(prefix+"severity", "must be between %d and %d, got %d", ALERT_SEVERITY_MIN, ALERT_SEVERITY_MAX, a.Severity)
+	}
+
+	if a.ReNotify && a.ReNotifyMinutes <= 0 {
+		errs.add(prefix+"reNotifyMinutes", "must be positive when reNotify is enabled")
+	}
+
+	if !a.ReNotify && a.ReNotifyMinutes != 0 {
+		errs.add(prefix+"reNotifyMinutes", "is set but reNotify is disabled")
+	}
+
+	if a.ReNotify && len(a.NotificationChannelIds) == 0 {
+		errs.add(prefix+"notificationChannelIds", "must not be empty when reNotify is enabled")
+	}
+
+	for i, id := range a.NotificationChannelIds {
+		if id <= 0 {
+			errs.add(fmt.Sprintf("%snotificationChannelIds[%d]", prefix, i), "invalid channel ID %d", id)
+		}
+	}
+
+	if len(a.SegmentBy) > 0 && a.SegmentCondition != nil {
+		switch a.SegmentCondition.Type {
+		case "ANY", "ALL":
+		default:
+			errs.add(prefix+"segmentCondition.type", "must be ANY or ALL, got %q", a.SegmentCondition.Type)
+		}
+	}
+
+	if msg := checkExpressionSyntax(a.Condition); msg != "" {
+		errs.add(prefix+"condition", "%s", msg)
+	}
+
+	if msg := checkExpressionSyntax(a.Filter); msg != "" {
+		errs.add(prefix+"filter", "%s", msg)
+	}
+
+	return errs
+}
+
+// checkExpressionSyntax checks that quotes and brackets of a condition, filter or PromQL expression are balanced
+// and returns a description of the first problem found
+func checkExpressionSyntax(expr string) string {
+	closing := map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+	var stack []rune
+	var quote rune
+	escaped := false
+
+	for i, r := range expr {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch r {
+		case '"', '\'', '`':
+			quote = r
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closing[r] {
+				return fmt.Sprintf("unexpected %q at position %d", r, i)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Sprintf("unterminated %c quote", quote)
+	}
+
+	if len(stack) > 0 {
+		return fmt.Sprintf("unclosed %q", stack[len(stack)-1])
+	}
+
+	return ""
+}
+
+// Validate checks the email notification channel options
+func (nc *EmailNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	if len(nc.EmailRecipients) == 0 {
+		errs.add("options.emailRecipients", "is required")
+	}
+
+	for i, addr := range nc.EmailRecipients {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			errs.add(fmt.Sprintf("options.emailRecipients[%d]", i), "invalid email address %q", addr)
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate checks the PagerDuty notification channel options
+func (nc *PagerDutyNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	requireField(&errs, "options.account", nc.Account)
+	requireField(&errs, "options.serviceKey", string(nc.ServiceKey))
+	requireField(&errs, "options.serviceName", nc.ServiceName)
+
+	return errs.err()
+}
+
+// Validate checks the Slack notification channel options
+func (nc *SlackNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	checkSecretURL(&errs, "options.url", nc.URL)
+
+	return errs.err()
+}
+
+// Validate checks the webhook notification channel options
+func (nc *WebhookNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	checkURL(&errs, "options.url", nc.URL, false)
+	checkHeaders(&errs, "options.additionalHeaders", nc.AdditionalHeaders)
+
+	return errs.err()
+}
+
+// Validate checks the OpsGenie notification channel options
+func (nc *OpsGenieNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	requireField(&errs, "options.apiKey", string(nc.APIKey))
+
+	switch nc.Region {
+	case "", "US", "EU":
+	default:
+		errs.add("options.region", "must be US or EU, got %q", nc.Region)
+	}
+
+	return errs.err()
+}
+
+// Validate checks the VictorOps notification channel options
+func (nc *VictorOpsNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	requireField(&errs, "options.apiKey", string(nc.APIKey))
+	requireField(&errs, "options.routingKey", nc.RoutingKey)
+
+	return errs.err()
+}
+
+// Validate checks the Microsoft Teams notification channel options
+func (nc *MSTeamsNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	checkSecretURL(&errs, "options.url", nc.URL)
+
+	return errs.err()
+}
+
+// Validate checks the Amazon SNS notification channel options
+func (nc *SNSNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	if len(nc.SnsTopicARNs) == 0 {
+		errs.add("options.snsTopicARNs", "is required")
+	}
+
+	for i, arn := range nc.SnsTopicARNs {
+		if !strings.HasPrefix(arn, "arn:") || !strings.Contains(arn, ":sns:") {
+			errs.add(fmt.Sprintf("options.snsTopicARNs[%d]", i), "invalid SNS topic ARN %q", arn)
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate checks the IBM Cloud Event Notifications notification channel options
+func (nc *IBMEventNotificationsNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	requireField(&errs, "options.instanceId", nc.InstanceID)
+
+	return errs.err()
+}
+
+// Validate checks the Prometheus Alertmanager notification channel options
+func (nc *PrometheusAlertManagerNotificationChannelOptions) Validate() error {
+	var errs ValidationErrors
+
+	checkURL(&errs, "options.url", nc.URL, false)
+	checkHeaders(&errs, "options.additionalHeaders", nc.AdditionalHeaders)
+
+	return errs.err()
+}
+
+func requireField(errs *ValidationErrors, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		errs.add(field, "is required")
+	}
+}
+
+// checkURL checks that value is an absolute http or https URL, https only when secure is set
+func checkURL(errs *ValidationErrors, field, value string, secure bool) {
+	if value == "" {
+		errs.add(field, "is required")
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		errs.add(field, "invalid URL %q", value)
+		return
+	}
+
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && !secure:
+	default:
+		errs.add(field, "unsupported URL scheme %q", u.Scheme)
+	}
+}
+
+// checkSecretURL checks that value is an absolute https URL without revealing it in errors,
+// secret references are resolved later and are not checked
+func checkSecretURL(errs *ValidationErrors, field string, value Secret) {
+	if value == "" {
+		errs.add(field, "is required")
+		return
+	}
+
+	if value.IsReference() {
+		return
+	}
+
+	u, err := url.Parse(string(value))
+	if err != nil || u.Host == "" {
+		errs.add(field, "invalid URL")
+		return
+	}
+
+	if u.Scheme != "https" {
+		errs.add(field, "unsupported URL scheme %q", u.Scheme)
+	}
+}
+
+func checkHeaders(errs *ValidationErrors, field string, headers map[string]Secret) {
+	for k := range headers {
+		if k == "" || strings.ContainsAny(k, " :\r\n") {
+			errs.add(field, "invalid header name %q", k)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package webhook implements a receiver of Sysdig Monitoring webhook notifications.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/WojtekTomaszewski/sdclient/sdclient"
+)
+
+// DefaultMaxBodySize is the largest notification payload accepted when Receiver.MaxBodySize is not set
+const DefaultMaxBodySize = 1 << 20
+
+// Notification is a notification sent by a Sysdig WEBHOOK notification channel
+type Notification struct {
+	Timestamp time.Time
+	Timespan  time.Duration
+	State     string
+	Resolved  bool
+	Alert     Alert
+	Event     Event
+	Entities  []Entity
+	Condition string
+	Source    string
+}
+
+// Alert describes the alert that triggered a notification
+type Alert struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Scope         string `json:"scope,omitempty"`
+	Severity      int    `json:"severity"`
+	SeverityLabel string `json:"severityLabel,omitempty"`
+	EditURL       string `json:"editUrl,omitempty"`
+}
+
+// Event describes the event created for a notification
+type Event struct {
+	ID  int    `json:"id"`
+	URL string `json:"url,omitempty"`
+}
+
+// Entity is a single entity of the alert scope that triggered the notification
+type Entity struct {
+	Entity         string           `json:"entity"`
+	MetricValues   []MetricValue    `json:"metricValues,omitempty"`
+	AdditionalInfo []AdditionalInfo `json:"additionalInfo,omitempty"`
+}
+
+// MetricValue is the value of a metric of the alert condition
+type MetricValue struct {
+	Metric           string  `json:"metric"`
+	Aggregation      string  `json:"aggregation,omitempty"`
+	GroupAggregation string  `json:"groupAggregation,omitempty"`
+	Value            float64 `json:"value"`
+}
+
+// AdditionalInfo is a label of the entity
+type AdditionalInfo struct {
+	Metric string `json:"metric"`
+	Value  string `json:"value"`
+}
+
+// IsActive reports whether the notification is about a triggered alert
+func (n *Notification) IsActive() bool {
+	return n.State == sdclient.ALERT_EVENT_STATE_ACTIVE && !n.Resolved
+}
+
+// Labels returns additional info of all entities as a map of label values
+func (n *Notification) Labels() map[string]string {
+	labels := make(map[string]string)
+	for _, e := range n.Entities {
+		for _, info := range e.AdditionalInfo {
+			labels[info.Metric] = info.Value
+		}
+	}
+	return labels
+}
+
+// notificationJSON is the wire format of Notification, timestamp and timespan are in microseconds
+type notificationJSON struct {
+	Timestamp int64    `json:"timestamp"`
+	Timespan  int64    `json:"timespan"`
+	State     string   `json:"state"`
+	Resolved  bool     `json:"resolved"`
+	Alert     Alert    `json:"alert"`
+	Event     Event    `json:"event"`
+	Entities  []Entity `json:"entities"`
+	Condition string   `json:"condition"`
+	Source    string   `json:"source"`
+}
+
+// UnmarshalJSON decodes a notification payload converting timestamps to time types
+func (n *Notification) UnmarshalJSON(data []byte) error {
+	var raw notificationJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*n = Notification{
+		Timespan:  time.Duration(raw.Timespan) * time.Microsecond,
+		State:     raw.State,
+		Resolved:  raw.Resolved,
+		Alert:     raw.Alert,
+		Event:     raw.Event,
+		Entities:  raw.Entities,
+		Condition: raw.Condition,
+		Source:    raw.Source,
+	}
+
+	if raw.Timestamp != 0 {
+		n.Timestamp = time.Unix(0, raw.Timestamp*int64(time.Microsecond))
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes a notification in the Sysdig payload format
+func (n Notification) MarshalJSON() ([]byte, error) {
+	raw := notificationJSON{
+		Timespan:  int64(n.Timespan / time.Microsecond),
+		State:     n.State,
+		Resolved:  n.Resolved,
+		Alert:     n.Alert,
+		Event:     n.Event,
+		Entities:  n.Entities,
+		Condition: n.Condition,
+		Source:    n.Source,
+	}
+
+	if !n.Timestamp.IsZero() {
+		raw.Timestamp = n.Timestamp.UnixNano() / int64(time.Microsecond)
+	}
+
+	return json.Marshal(raw)
+}
+
+// HandlerFunc is called for every received notification, an error makes the receiver respond with status 500
+type HandlerFunc func(ctx context.Context, n *Notification) error
+
+// Receiver is an http.Handler receiving Sysdig webhook notifications
+type Receiver struct {
+	// SecretHeader and SharedSecret configure a header that must be set to the shared secret,
+	// matching the additional headers of the WEBHOOK notification channel
+	SecretHeader string
+	SharedSecret string
+	// Username and Password configure required basic authentication
+	Username string
+	Password string
+	// MaxBodySize limits the payload size, DefaultMaxBodySize if zero
+	MaxBodySize int64
+
+	onNotification []HandlerFunc
+	onActive       []HandlerFunc
+	onResolved     []HandlerFunc
+}
+
+// NewReceiver creates a new webhook notification receiver.
+func NewReceiver() *Receiver {
+	return &Receiver{}
+}
+
+// WithSharedSecret requires header to be set to secret on every request.
+// Only one of header and secret being empty makes the receiver refuse every request, see Validate.
+func (rc *Receiver) WithSharedSecret(header, secret string) *Receiver {
+	rc.SecretHeader = header
+	rc.SharedSecret = secret
+	return rc
+}
+
+// WithBasicAuth requires basic authentication on every request.
+// Only one of username and password being empty makes the receiver refuse every request, see Validate.
+func (rc *Receiver) WithBasicAuth(username, password string) *Receiver {
+	rc.Username = username
+	rc.Password = password
+	return rc
+}
+
+// OnNotification registers a handler called for every notification.
+func (rc *Receiver) OnNotification(fn HandlerFunc) *Receiver {
+	rc.onNotification = append(rc.onNotification, fn)
+	return rc
+}
+
+// OnActive registers a handler called for notifications of triggered alerts.
+func (rc *Receiver) OnActive(fn HandlerFunc) *Receiver {
+	rc.onActive = append(rc.onActive, fn)
+	return rc
+}
+
+// OnResolved registers a handler called for notifications of resolved alerts.
+func (rc *Receiver) OnResolved(fn HandlerFunc) *Receiver {
+	rc.onResolved = append(rc.onResolved, fn)
+	return rc
+}
+
+// Validate reports an incomplete authentication configuration. A receiver without any authentication configured is valid.
+func (rc *Receiver) Validate() error {
+	if rc.SharedSecret != "" && rc.SecretHeader == "" {
+		return errors.New("webhook: SecretHeader is required with SharedSecret")
+	}
+
+	if rc.SecretHeader != "" && rc.SharedSecret == "" {
+		return errors.New("webhook: SharedSecret is required with SecretHeader")
+	}
+
+	if rc.Password != "" && rc.Username == "" {
+		return errors.New("webhook: Username is required with Password")
+	}
+
+	if rc.Username != "" && rc.Password == "" {
+		return errors.New("webhook: Password is required with Username")
+	}
+
+	return nil
+}
+
+// ServeHTTP authenticates and parses a notification and dispatches it to the registered handlers.
+// Every request is refused with status 500 while the receiver fails Validate.
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := rc.Validate(); err != nil {
+		http.Error(w, "webhook receiver misconfigured", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !rc.authorized(r) {
+		if rc.Username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="sysdig"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	n, err := rc.parse(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := rc.dispatch(r.Context(), n); err != nil {
+		http.Error(w, "notification handler failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Receiver) authorized(r *http.Request) bool {
+	if rc.SharedSecret != "" {
+		if !secureEqual(r.Header.Get(rc.SecretHeader), rc.SharedSecret) {
+			return false
+		}
+	}
+
+	if rc.Username != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || !secureEqual(username, rc.Username) || !secureEqual(password, rc.Password) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rc *Receiver) parse(r *http.Request) (*Notification, error) {
+	limit := rc.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("payload exceeds %d bytes", limit)
+	}
+
+	var n = new(Notification)
+
+	if err := json.Unmarshal(body, n); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+
+	return n, nil
+}
+
+func (rc *Receiver) dispatch(ctx context.Context, n *Notification) error {
+	handlers := rc.onNotification
+
+	if n.IsActive() {
+		handlers = append(handlers[:len(handlers):len(handlers)], rc.onActive...)
+	} else {
+		handlers = append(handlers[:len(handlers):len(handlers)], rc.onResolved...)
+	}
+
+	for _, fn := range handlers {
+		if err := fn(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}